@@ -47,6 +47,14 @@ func main() {
 	analyzer := semantic.NewAnalyzer()
 	errors := analyzer.Analyze(program)
 
+	// Warnings do not stop the run
+	if len(analyzer.Warnings) > 0 {
+		fmt.Println("Semantic warnings:")
+		for _, warning := range analyzer.Warnings {
+			fmt.Printf("  %s\n", warning)
+		}
+	}
+
 	if len(errors) > 0 {
 		fmt.Println("Semantic errors:")
 		for _, err := range errors {
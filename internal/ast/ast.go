@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
+	"strconv"
 	"strings"
 
 	"ixion/internal/token"
@@ -11,6 +13,8 @@ import (
 type Node interface {
 	// TokenLiteral returns the literal value of the token associated with the node.
 	TokenLiteral() string
+	// Span returns the source range covered by the node.
+	Span() token.Span
 	String() string
 }
 
@@ -47,6 +51,14 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+func (p *Program) Span() token.Span {
+	var span token.Span
+	for _, s := range p.Statements {
+		span = span.Join(s.Span())
+	}
+	return span
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 	for _, s := range p.Statements {
@@ -58,16 +70,19 @@ func (p *Program) String() string {
 // --- Statements ---
 
 // VarStatement represents a variable declaration statement.
-// e.g., var x = 5; or var x int = 5;
+// e.g., var x = 5; var x int = 5; or var x int;
 type VarStatement struct {
 	Token token.Token // the token.VAR token
+	Loc   token.Span
+	Doc   string // Doc comment directly preceding the statement, if any
 	Name  *Identifier
 	Type  TypeExpression // Optional type annotation
-	Value Expression
+	Value Expression     // nil if the variable is assigned later
 }
 
 func (vs *VarStatement) statementNode()       {}
 func (vs *VarStatement) TokenLiteral() string { return vs.Token.Text }
+func (vs *VarStatement) Span() token.Span     { return vs.Loc }
 func (vs *VarStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(vs.TokenLiteral() + " ")
@@ -77,9 +92,8 @@ func (vs *VarStatement) String() string {
 	if vs.Type != nil {
 		out.WriteString(" " + vs.Type.String())
 	}
-	out.WriteString(" = ")
 	if vs.Value != nil {
-		out.WriteString(vs.Value.String())
+		out.WriteString(" = " + vs.Value.String())
 	}
 	out.WriteString(";")
 	return out.String()
@@ -89,6 +103,8 @@ func (vs *VarStatement) String() string {
 // e.g., const x = 5; or const x int = 5;
 type ConstStatement struct {
 	Token token.Token // the token.CONST token
+	Loc   token.Span
+	Doc   string // Doc comment directly preceding the statement, if any
 	Name  *Identifier
 	Type  TypeExpression // Optional type annotation
 	Value Expression
@@ -96,6 +112,7 @@ type ConstStatement struct {
 
 func (cs *ConstStatement) statementNode()       {}
 func (cs *ConstStatement) TokenLiteral() string { return cs.Token.Text }
+func (cs *ConstStatement) Span() token.Span     { return cs.Loc }
 func (cs *ConstStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(cs.TokenLiteral() + " ")
@@ -111,15 +128,34 @@ func (cs *ConstStatement) String() string {
 	return out.String()
 }
 
+// TypeDeclaration represents a type declaration statement.
+// e.g., type Point struct { x int; y int };
+type TypeDeclaration struct {
+	Token token.Token // the token.TYPE token
+	Loc   token.Span
+	Doc   string // Doc comment directly preceding the declaration, if any
+	Name  *Identifier
+	Type  TypeExpression
+}
+
+func (td *TypeDeclaration) statementNode()       {}
+func (td *TypeDeclaration) TokenLiteral() string { return td.Token.Text }
+func (td *TypeDeclaration) Span() token.Span     { return td.Loc }
+func (td *TypeDeclaration) String() string {
+	return td.TokenLiteral() + " " + td.Name.String() + " " + td.Type.String() + ";"
+}
+
 // ReturnStatement represents a return statement.
 // e.g., return 10;
 type ReturnStatement struct {
 	Token       token.Token // the 'return' token
+	Loc         token.Span
 	ReturnValue Expression
 }
 
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Text }
+func (rs *ReturnStatement) Span() token.Span     { return rs.Loc }
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(rs.TokenLiteral() + " ")
@@ -134,11 +170,13 @@ func (rs *ReturnStatement) String() string {
 // e.g., x + 10;
 type ExpressionStatement struct {
 	Token      token.Token // the first token of the expression
+	Loc        token.Span
 	Expression Expression
 }
 
 func (es *ExpressionStatement) statementNode()       {}
 func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Text }
+func (es *ExpressionStatement) Span() token.Span     { return es.Loc }
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
 		return es.Expression.String()
@@ -150,11 +188,13 @@ func (es *ExpressionStatement) String() string {
 // e.g., { statement1; statement2; }
 type BlockStatement struct {
 	Token      token.Token // the { token
+	Loc        token.Span
 	Statements []Statement
 }
 
 func (bs *BlockStatement) statementNode()       {}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Text }
+func (bs *BlockStatement) Span() token.Span     { return bs.Loc }
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 	for _, s := range bs.Statements {
@@ -167,11 +207,13 @@ func (bs *BlockStatement) String() string {
 // e.g., print "hello world";
 type PrintStatement struct {
 	Token token.Token // the 'print' token
+	Loc   token.Span
 	Value Expression
 }
 
 func (ps *PrintStatement) statementNode()       {}
 func (ps *PrintStatement) TokenLiteral() string { return ps.Token.Text }
+func (ps *PrintStatement) Span() token.Span     { return ps.Loc }
 func (ps *PrintStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(ps.TokenLiteral() + "(")
@@ -183,21 +225,134 @@ func (ps *PrintStatement) String() string {
 }
 
 // ForStatement represents a for loop statement.
-// e.g., for (x < y) { ... }
+// Init, Condition and Post are all optional.
+// e.g., for { ... }, for x < y { ... } or for var i = 0; i < n; i = i + 1 { ... }
 type ForStatement struct {
 	Token     token.Token // the 'for' token
+	Loc       token.Span
+	Init      Statement
 	Condition Expression
+	Post      Statement
 	Body      *BlockStatement
 }
 
 func (fs *ForStatement) statementNode()       {}
 func (fs *ForStatement) TokenLiteral() string { return fs.Token.Text }
+func (fs *ForStatement) Span() token.Span     { return fs.Loc }
 func (fs *ForStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString("for")
-	out.WriteString(fs.Condition.String())
-	out.WriteString(" ")
+	if fs.Init != nil || fs.Post != nil {
+		out.WriteString(" ")
+		if fs.Init != nil {
+			out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+		}
+		out.WriteString("; ")
+		if fs.Condition != nil {
+			out.WriteString(fs.Condition.String())
+		}
+		out.WriteString("; ")
+		if fs.Post != nil {
+			out.WriteString(fs.Post.String())
+		}
+	} else if fs.Condition != nil {
+		out.WriteString(" ")
+		out.WriteString(fs.Condition.String())
+	}
+	out.WriteString(" {")
 	out.WriteString(fs.Body.String())
+	out.WriteString("}")
+	return out.String()
+}
+
+// IfStatement represents a conditional statement with an optional else
+// branch. Alternative is either another *IfStatement (else if) or a
+// *BlockStatement (else).
+// e.g., if x < y { ... } else if x > y { ... } else { ... }
+type IfStatement struct {
+	Token       token.Token // the 'if' token
+	Loc         token.Span
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative Statement
+}
+
+func (is *IfStatement) statementNode()       {}
+func (is *IfStatement) TokenLiteral() string { return is.Token.Text }
+func (is *IfStatement) Span() token.Span     { return is.Loc }
+func (is *IfStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("if ")
+	out.WriteString(is.Condition.String())
+	out.WriteString(" {")
+	out.WriteString(is.Consequence.String())
+	out.WriteString("}")
+	if is.Alternative != nil {
+		out.WriteString(" else ")
+		if _, ok := is.Alternative.(*BlockStatement); ok {
+			out.WriteString("{" + is.Alternative.String() + "}")
+		} else {
+			out.WriteString(is.Alternative.String())
+		}
+	}
+	return out.String()
+}
+
+// BreakStatement represents a break statement with an optional label.
+// e.g., break; or break outer;
+type BreakStatement struct {
+	Token token.Token // the 'break' token
+	Loc   token.Span
+	Label *Identifier // Optional
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Text }
+func (bs *BreakStatement) Span() token.Span     { return bs.Loc }
+func (bs *BreakStatement) String() string {
+	if bs.Label != nil {
+		return bs.TokenLiteral() + " " + bs.Label.String() + ";"
+	}
+	return bs.TokenLiteral() + ";"
+}
+
+// ContinueStatement represents a continue statement with an optional label.
+// e.g., continue; or continue outer;
+type ContinueStatement struct {
+	Token token.Token // the 'continue' token
+	Loc   token.Span
+	Label *Identifier // Optional
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Text }
+func (cs *ContinueStatement) Span() token.Span     { return cs.Loc }
+func (cs *ContinueStatement) String() string {
+	if cs.Label != nil {
+		return cs.TokenLiteral() + " " + cs.Label.String() + ";"
+	}
+	return cs.TokenLiteral() + ";"
+}
+
+// LabeledStatement represents a statement preceded by a label.
+// e.g., outer: for { ... }
+type LabeledStatement struct {
+	Token     token.Token // the label's token.IDENT token
+	Loc       token.Span
+	Label     *Identifier
+	Statement Statement
+}
+
+func (ls *LabeledStatement) statementNode()       {}
+func (ls *LabeledStatement) TokenLiteral() string { return ls.Token.Text }
+func (ls *LabeledStatement) Span() token.Span     { return ls.Loc }
+func (ls *LabeledStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString(ls.Label.String())
+	out.WriteString(": ")
+	if ls.Statement != nil {
+		out.WriteString(ls.Statement.String())
+	}
 	return out.String()
 }
 
@@ -206,54 +361,119 @@ func (fs *ForStatement) String() string {
 // Identifier represents an identifier (e.g., a variable name).
 type Identifier struct {
 	Token token.Token // the token.IDENT token
+	Loc   token.Span
 	Value string
 }
 
 func (i *Identifier) expressionNode()      {}
 func (i *Identifier) TokenLiteral() string { return i.Token.Text }
+func (i *Identifier) Span() token.Span     { return i.Loc }
 func (i *Identifier) String() string       { return i.Value }
 
 // IntegerLiteral represents an integer literal.
+// e.g., 42, 0xFF, 0o17, 0b1010 or 1_000_000
+// Its value is not limited in size; the analyzer checks that it fits the
+// type it is used as.
 type IntegerLiteral struct {
 	Token token.Token
-	Value int64
+	Loc   token.Span
+	Value *big.Int
 }
 
 func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Text }
+func (il *IntegerLiteral) Span() token.Span     { return il.Loc }
 func (il *IntegerLiteral) String() string       { return il.Token.Text }
 
+// FloatLiteral represents a floating-point literal.
+// e.g., 1.5 or 2e-3
+type FloatLiteral struct {
+	Token token.Token
+	Loc   token.Span
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Text }
+func (fl *FloatLiteral) Span() token.Span     { return fl.Loc }
+func (fl *FloatLiteral) String() string       { return fl.Token.Text }
+
 // StringLiteral represents a string literal.
 type StringLiteral struct {
 	Token token.Token
+	Loc   token.Span
 	Value string
 }
 
 func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Text }
-func (sl *StringLiteral) String() string       { return "\"" + sl.Value + "\"" }
+func (sl *StringLiteral) Span() token.Span     { return sl.Loc }
+func (sl *StringLiteral) String() string       { return strconv.Quote(sl.Value) }
+
+// BooleanLiteral represents a boolean literal.
+// e.g., true or false
+type BooleanLiteral struct {
+	Token token.Token
+	Loc   token.Span
+	Value bool
+}
+
+func (bl *BooleanLiteral) expressionNode()      {}
+func (bl *BooleanLiteral) TokenLiteral() string { return bl.Token.Text }
+func (bl *BooleanLiteral) Span() token.Span     { return bl.Loc }
+func (bl *BooleanLiteral) String() string       { return strconv.FormatBool(bl.Value) }
 
 // TypeLiteral represents a type literal (e.g., int, string)
 type TypeLiteral struct {
 	Token token.Token
+	Loc   token.Span
 	Value string
 }
 
 func (tl *TypeLiteral) typeNode()            {}
 func (tl *TypeLiteral) expressionNode()      {}
 func (tl *TypeLiteral) TokenLiteral() string { return tl.Token.Text }
+func (tl *TypeLiteral) Span() token.Span     { return tl.Loc }
 func (tl *TypeLiteral) String() string       { return tl.Value }
 
+// FunctionType represents the type of a function.
+// e.g., fn(int, string) bool
+type FunctionType struct {
+	Token  token.Token // The 'fn' token
+	Loc    token.Span
+	Params []TypeExpression
+	Result TypeExpression // nil if the function has no return type
+}
+
+func (ft *FunctionType) typeNode()            {}
+func (ft *FunctionType) expressionNode()      {}
+func (ft *FunctionType) TokenLiteral() string { return ft.Token.Text }
+func (ft *FunctionType) Span() token.Span     { return ft.Loc }
+func (ft *FunctionType) String() string {
+	params := []string{}
+	for _, p := range ft.Params {
+		params = append(params, p.String())
+	}
+
+	out := "fn(" + strings.Join(params, ", ") + ")"
+	if ft.Result != nil {
+		out += " " + ft.Result.String()
+	}
+	return out
+}
+
 // PrefixExpression represents a unary operation.
-// e.g., -15
+// e.g., -15 or !ok
 type PrefixExpression struct {
 	Token    token.Token // The prefix token, e.g. -
+	Loc      token.Span
 	Operator string
 	Right    Expression
 }
 
 func (pe *PrefixExpression) expressionNode()      {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Text }
+func (pe *PrefixExpression) Span() token.Span     { return pe.Loc }
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
@@ -267,6 +487,7 @@ func (pe *PrefixExpression) String() string {
 // e.g., 5 + 5
 type InfixExpression struct {
 	Token    token.Token // The operator token, e.g. +
+	Loc      token.Span
 	Left     Expression
 	Operator string
 	Right    Expression
@@ -274,6 +495,7 @@ type InfixExpression struct {
 
 func (ie *InfixExpression) expressionNode()      {}
 func (ie *InfixExpression) TokenLiteral() string { return ie.Token.Text }
+func (ie *InfixExpression) Span() token.Span     { return ie.Loc }
 func (ie *InfixExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
@@ -287,12 +509,14 @@ func (ie *InfixExpression) String() string {
 // FunctionParameter represents a function parameter with type
 type FunctionParameter struct {
 	Token token.Token
+	Loc   token.Span
 	Name  *Identifier
 	Type  TypeExpression
 }
 
 func (fp *FunctionParameter) expressionNode()      {}
 func (fp *FunctionParameter) TokenLiteral() string { return fp.Token.Text }
+func (fp *FunctionParameter) Span() token.Span     { return fp.Loc }
 func (fp *FunctionParameter) String() string {
 	if fp.Type == nil {
 		return fp.Name.String()
@@ -305,6 +529,7 @@ func (fp *FunctionParameter) String() string {
 // e.g., fn(x int, y int) int { x + y; }
 type FunctionLiteral struct {
 	Token      token.Token // The 'fn' token
+	Loc        token.Span
 	Parameters []*FunctionParameter
 	ReturnType TypeExpression
 	Body       *BlockStatement
@@ -312,6 +537,7 @@ type FunctionLiteral struct {
 
 func (fl *FunctionLiteral) expressionNode()      {}
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Text }
+func (fl *FunctionLiteral) Span() token.Span     { return fl.Loc }
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 	params := []string{}
@@ -334,12 +560,14 @@ func (fl *FunctionLiteral) String() string {
 // e.g., add(2, 3)
 type CallExpression struct {
 	Token     token.Token // The '(' token
-	Function  Expression  // Identifier or FunctionLiteral
+	Loc       token.Span
+	Function  Expression // Identifier or FunctionLiteral
 	Arguments []Expression
 }
 
 func (ce *CallExpression) expressionNode()      {}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Text }
+func (ce *CallExpression) Span() token.Span     { return ce.Loc }
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 	args := []string{}
@@ -353,8 +581,243 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// ConversionExpression represents an explicit type conversion.
+// e.g., int8(x)
+type ConversionExpression struct {
+	Token token.Token // The type token
+	Loc   token.Span
+	Type  *TypeLiteral
+	Value Expression
+}
+
+func (ce *ConversionExpression) expressionNode()      {}
+func (ce *ConversionExpression) TokenLiteral() string { return ce.Token.Text }
+func (ce *ConversionExpression) Span() token.Span     { return ce.Loc }
+func (ce *ConversionExpression) String() string {
+	return ce.Type.String() + "(" + ce.Value.String() + ")"
+}
+
+// ArrayType represents an array or a slice type.
+// e.g., [3]int or []string
+type ArrayType struct {
+	Token token.Token // The '[' token
+	Loc   token.Span
+	Len   Expression // nil for a slice type
+	Elem  TypeExpression
+}
+
+func (at *ArrayType) typeNode()            {}
+func (at *ArrayType) expressionNode()      {}
+func (at *ArrayType) TokenLiteral() string { return at.Token.Text }
+func (at *ArrayType) Span() token.Span     { return at.Loc }
+func (at *ArrayType) String() string {
+	if at.Len == nil {
+		return "[]" + at.Elem.String()
+	}
+	return "[" + at.Len.String() + "]" + at.Elem.String()
+}
+
+// MapType represents a map type.
+// e.g., map[string]int
+type MapType struct {
+	Token token.Token // The 'map' token
+	Loc   token.Span
+	Key   TypeExpression
+	Value TypeExpression
+}
+
+func (mt *MapType) typeNode()            {}
+func (mt *MapType) expressionNode()      {}
+func (mt *MapType) TokenLiteral() string { return mt.Token.Text }
+func (mt *MapType) Span() token.Span     { return mt.Loc }
+func (mt *MapType) String() string {
+	return "map[" + mt.Key.String() + "]" + mt.Value.String()
+}
+
+// StructType represents a struct type.
+// e.g., struct { x int; y int }
+type StructType struct {
+	Token  token.Token // The 'struct' token
+	Loc    token.Span
+	Fields []*StructField
+}
+
+func (st *StructType) typeNode()            {}
+func (st *StructType) expressionNode()      {}
+func (st *StructType) TokenLiteral() string { return st.Token.Text }
+func (st *StructType) Span() token.Span     { return st.Loc }
+func (st *StructType) String() string {
+	fields := []string{}
+	for _, f := range st.Fields {
+		fields = append(fields, f.String())
+	}
+	return "struct{" + strings.Join(fields, "; ") + "}"
+}
+
+// StructField represents a field of a struct type.
+// e.g., x int
+type StructField struct {
+	Token token.Token // The field name token
+	Loc   token.Span
+	Name  *Identifier
+	Type  TypeExpression
+}
+
+func (sf *StructField) expressionNode()      {}
+func (sf *StructField) TokenLiteral() string { return sf.Token.Text }
+func (sf *StructField) Span() token.Span     { return sf.Loc }
+func (sf *StructField) String() string       { return sf.Name.String() + " " + sf.Type.String() }
+
+// StructLiteral represents a value of a struct type.
+// e.g., Point{x: 1, y: 2}
+type StructLiteral struct {
+	Token  token.Token // The '{' token
+	Loc    token.Span
+	Type   Expression // The name of the struct type
+	Fields []*FieldValue
+}
+
+func (sl *StructLiteral) expressionNode()      {}
+func (sl *StructLiteral) TokenLiteral() string { return sl.Token.Text }
+func (sl *StructLiteral) Span() token.Span     { return sl.Loc }
+func (sl *StructLiteral) String() string {
+	fields := []string{}
+	for _, f := range sl.Fields {
+		fields = append(fields, f.String())
+	}
+	return sl.Type.String() + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// FieldValue represents a field of a struct literal.
+// e.g., x: 1
+type FieldValue struct {
+	Token token.Token // The field name token
+	Loc   token.Span
+	Name  *Identifier
+	Value Expression
+}
+
+func (fv *FieldValue) expressionNode()      {}
+func (fv *FieldValue) TokenLiteral() string { return fv.Token.Text }
+func (fv *FieldValue) Span() token.Span     { return fv.Loc }
+func (fv *FieldValue) String() string       { return fv.Name.String() + ": " + fv.Value.String() }
+
+// SelectorExpression represents access to a field of a struct value.
+// e.g., p.x
+type SelectorExpression struct {
+	Token token.Token // The '.' token
+	Loc   token.Span
+	Left  Expression
+	Field *Identifier
+}
+
+func (se *SelectorExpression) expressionNode()      {}
+func (se *SelectorExpression) TokenLiteral() string { return se.Token.Text }
+func (se *SelectorExpression) Span() token.Span     { return se.Loc }
+func (se *SelectorExpression) String() string {
+	return se.Left.String() + "." + se.Field.String()
+}
+
+// ArrayLiteral represents a list of values.
+// e.g., [1, 2, 3]
+type ArrayLiteral struct {
+	Token    token.Token // The '[' token
+	Loc      token.Span
+	Elements []Expression
+}
+
+func (al *ArrayLiteral) expressionNode()      {}
+func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Text }
+func (al *ArrayLiteral) Span() token.Span     { return al.Loc }
+func (al *ArrayLiteral) String() string {
+	elements := []string{}
+	for _, e := range al.Elements {
+		elements = append(elements, e.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// MapLiteral represents a map value.
+// e.g., {"one": 1, "two": 2}
+type MapLiteral struct {
+	Token token.Token // The '{' token
+	Loc   token.Span
+	Pairs []*KeyValue
+}
+
+func (ml *MapLiteral) expressionNode()      {}
+func (ml *MapLiteral) TokenLiteral() string { return ml.Token.Text }
+func (ml *MapLiteral) Span() token.Span     { return ml.Loc }
+func (ml *MapLiteral) String() string {
+	pairs := []string{}
+	for _, kv := range ml.Pairs {
+		pairs = append(pairs, kv.String())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// KeyValue represents an entry of a map literal.
+// e.g., "one": 1
+type KeyValue struct {
+	Token token.Token // The ':' token
+	Loc   token.Span
+	Key   Expression
+	Value Expression
+}
+
+func (kv *KeyValue) expressionNode()      {}
+func (kv *KeyValue) TokenLiteral() string { return kv.Token.Text }
+func (kv *KeyValue) Span() token.Span     { return kv.Loc }
+func (kv *KeyValue) String() string       { return kv.Key.String() + ": " + kv.Value.String() }
+
+// IndexExpression represents access to an element of an array or slice,
+// or to the value of a key in a map.
+// e.g., a[i] or m["key"]
+type IndexExpression struct {
+	Token token.Token // The '[' token
+	Loc   token.Span
+	Left  Expression
+	Index Expression
+}
+
+func (ie *IndexExpression) expressionNode()      {}
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Text }
+func (ie *IndexExpression) Span() token.Span     { return ie.Loc }
+func (ie *IndexExpression) String() string {
+	return ie.Left.String() + "[" + ie.Index.String() + "]"
+}
+
+// SliceExpression represents a part of an array or slice.
+// e.g., a[i:j], a[:j] or a[i:]
+type SliceExpression struct {
+	Token token.Token // The '[' token
+	Loc   token.Span
+	Left  Expression
+	Low   Expression // nil if omitted
+	High  Expression // nil if omitted
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Text }
+func (se *SliceExpression) Span() token.Span     { return se.Loc }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString(se.Left.String() + "[")
+	if se.Low != nil {
+		out.WriteString(se.Low.String())
+	}
+	out.WriteString(":")
+	if se.High != nil {
+		out.WriteString(se.High.String())
+	}
+	out.WriteString("]")
+	return out.String()
+}
+
 type FunctionDeclaration struct {
 	Token      token.Token
+	Loc        token.Span
+	Doc        string // Doc comment directly preceding the declaration, if any
 	Name       *Identifier
 	Parameters []*FunctionParameter
 	ReturnType TypeExpression
@@ -363,6 +826,7 @@ type FunctionDeclaration struct {
 
 func (fd *FunctionDeclaration) statementNode()       {}
 func (fd *FunctionDeclaration) TokenLiteral() string { return fd.Token.Text }
+func (fd *FunctionDeclaration) Span() token.Span     { return fd.Loc }
 func (fd *FunctionDeclaration) String() string {
 	var out bytes.Buffer
 	params := []string{}
@@ -385,12 +849,14 @@ func (fd *FunctionDeclaration) String() string {
 
 type AssignmentExpression struct {
 	Token token.Token
+	Loc   token.Span
 	Left  Expression
 	Value Expression
 }
 
 func (ae *AssignmentExpression) expressionNode()      {}
 func (ae *AssignmentExpression) TokenLiteral() string { return ae.Token.Text }
+func (ae *AssignmentExpression) Span() token.Span     { return ae.Loc }
 func (ae *AssignmentExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(ae.Left.String())
@@ -398,3 +864,24 @@ func (ae *AssignmentExpression) String() string {
 	out.WriteString(ae.Value.String())
 	return out.String()
 }
+
+// MultiAssignmentExpression assigns the values of a single expression to
+// several targets.
+// e.g., v, ok = m[k]
+type MultiAssignmentExpression struct {
+	Token token.Token // The '=' token
+	Loc   token.Span
+	Left  []Expression
+	Value Expression
+}
+
+func (ma *MultiAssignmentExpression) expressionNode()      {}
+func (ma *MultiAssignmentExpression) TokenLiteral() string { return ma.Token.Text }
+func (ma *MultiAssignmentExpression) Span() token.Span     { return ma.Loc }
+func (ma *MultiAssignmentExpression) String() string {
+	left := []string{}
+	for _, l := range ma.Left {
+		left = append(left, l.String())
+	}
+	return strings.Join(left, ", ") + " = " + ma.Value.String()
+}
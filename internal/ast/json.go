@@ -3,6 +3,7 @@ package ast
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 )
 
 // jsonProgram is an anonymous struct for JSON serialization of Program.
@@ -39,9 +40,9 @@ func (i *Identifier) MarshalJSON() ([]byte, error) {
 
 func (il *IntegerLiteral) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Type  string `json:"type"`
-		Token string `json:"token_literal"`
-		Value int64  `json:"value"`
+		Type  string   `json:"type"`
+		Token string   `json:"token_literal"`
+		Value *big.Int `json:"value"`
 	}{
 		Type:  "IntegerLiteral",
 		Token: il.TokenLiteral(),
@@ -49,6 +50,18 @@ func (il *IntegerLiteral) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (fl *FloatLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string  `json:"type"`
+		Token string  `json:"token_literal"`
+		Value float64 `json:"value"`
+	}{
+		Type:  "FloatLiteral",
+		Token: fl.TokenLiteral(),
+		Value: fl.Value,
+	})
+}
+
 func (sl *StringLiteral) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Type  string `json:"type"`
@@ -61,6 +74,18 @@ func (sl *StringLiteral) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (bl *BooleanLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string `json:"type"`
+		Token string `json:"token_literal"`
+		Value bool   `json:"value"`
+	}{
+		Type:  "BooleanLiteral",
+		Token: bl.TokenLiteral(),
+		Value: bl.Value,
+	})
+}
+
 func (tl *TypeLiteral) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Type  string `json:"type"`
@@ -73,6 +98,20 @@ func (tl *TypeLiteral) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (ft *FunctionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string           `json:"type"`
+		Token  string           `json:"token_literal"`
+		Params []TypeExpression `json:"params"`
+		Result TypeExpression   `json:"result,omitempty"`
+	}{
+		Type:   "FunctionType",
+		Token:  ft.TokenLiteral(),
+		Params: ft.Params,
+		Result: ft.Result,
+	})
+}
+
 func (pe *PrefixExpression) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Type     string     `json:"type"`
@@ -133,6 +172,184 @@ func (ce *CallExpression) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (ce *ConversionExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type       string       `json:"type"`
+		Token      string       `json:"token_literal"`
+		TargetType *TypeLiteral `json:"target_type"`
+		Value      Expression   `json:"value"`
+	}{
+		Type:       "ConversionExpression",
+		Token:      ce.TokenLiteral(),
+		TargetType: ce.Type,
+		Value:      ce.Value,
+	})
+}
+
+func (at *ArrayType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string         `json:"type"`
+		Token string         `json:"token_literal"`
+		Len   Expression     `json:"len,omitempty"`
+		Elem  TypeExpression `json:"elem"`
+	}{
+		Type:  "ArrayType",
+		Token: at.TokenLiteral(),
+		Len:   at.Len,
+		Elem:  at.Elem,
+	})
+}
+
+func (mt *MapType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type      string         `json:"type"`
+		Token     string         `json:"token_literal"`
+		Key       TypeExpression `json:"key"`
+		ValueType TypeExpression `json:"value_type"`
+	}{
+		Type:      "MapType",
+		Token:     mt.TokenLiteral(),
+		Key:       mt.Key,
+		ValueType: mt.Value,
+	})
+}
+
+func (st *StructType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string         `json:"type"`
+		Token  string         `json:"token_literal"`
+		Fields []*StructField `json:"fields"`
+	}{
+		Type:   "StructType",
+		Token:  st.TokenLiteral(),
+		Fields: st.Fields,
+	})
+}
+
+func (sf *StructField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type      string         `json:"type"`
+		Token     string         `json:"token_literal"`
+		Name      *Identifier    `json:"name"`
+		FieldType TypeExpression `json:"field_type"`
+	}{
+		Type:      "StructField",
+		Token:     sf.TokenLiteral(),
+		Name:      sf.Name,
+		FieldType: sf.Type,
+	})
+}
+
+func (sl *StructLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type       string        `json:"type"`
+		Token      string        `json:"token_literal"`
+		StructType Expression    `json:"struct_type"`
+		Fields     []*FieldValue `json:"fields"`
+	}{
+		Type:       "StructLiteral",
+		Token:      sl.TokenLiteral(),
+		StructType: sl.Type,
+		Fields:     sl.Fields,
+	})
+}
+
+func (fv *FieldValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string      `json:"type"`
+		Token string      `json:"token_literal"`
+		Name  *Identifier `json:"name"`
+		Value Expression  `json:"value"`
+	}{
+		Type:  "FieldValue",
+		Token: fv.TokenLiteral(),
+		Name:  fv.Name,
+		Value: fv.Value,
+	})
+}
+
+func (se *SelectorExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string      `json:"type"`
+		Token string      `json:"token_literal"`
+		Left  Expression  `json:"left"`
+		Field *Identifier `json:"field"`
+	}{
+		Type:  "SelectorExpression",
+		Token: se.TokenLiteral(),
+		Left:  se.Left,
+		Field: se.Field,
+	})
+}
+
+func (al *ArrayLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     string       `json:"type"`
+		Token    string       `json:"token_literal"`
+		Elements []Expression `json:"elements"`
+	}{
+		Type:     "ArrayLiteral",
+		Token:    al.TokenLiteral(),
+		Elements: al.Elements,
+	})
+}
+
+func (ml *MapLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string      `json:"type"`
+		Token string      `json:"token_literal"`
+		Pairs []*KeyValue `json:"pairs"`
+	}{
+		Type:  "MapLiteral",
+		Token: ml.TokenLiteral(),
+		Pairs: ml.Pairs,
+	})
+}
+
+func (kv *KeyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string     `json:"type"`
+		Token string     `json:"token_literal"`
+		Key   Expression `json:"key"`
+		Value Expression `json:"value"`
+	}{
+		Type:  "KeyValue",
+		Token: kv.TokenLiteral(),
+		Key:   kv.Key,
+		Value: kv.Value,
+	})
+}
+
+func (ie *IndexExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string     `json:"type"`
+		Token string     `json:"token_literal"`
+		Left  Expression `json:"left"`
+		Index Expression `json:"index"`
+	}{
+		Type:  "IndexExpression",
+		Token: ie.TokenLiteral(),
+		Left:  ie.Left,
+		Index: ie.Index,
+	})
+}
+
+func (se *SliceExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string     `json:"type"`
+		Token string     `json:"token_literal"`
+		Left  Expression `json:"left"`
+		Low   Expression `json:"low,omitempty"`
+		High  Expression `json:"high,omitempty"`
+	}{
+		Type:  "SliceExpression",
+		Token: se.TokenLiteral(),
+		Left:  se.Left,
+		Low:   se.Low,
+		High:  se.High,
+	})
+}
+
 func (ae *AssignmentExpression) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Type  string     `json:"type"`
@@ -147,6 +364,20 @@ func (ae *AssignmentExpression) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (ma *MultiAssignmentExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string       `json:"type"`
+		Token string       `json:"token_literal"`
+		Left  []Expression `json:"left"`
+		Value Expression   `json:"value"`
+	}{
+		Type:  "MultiAssignmentExpression",
+		Token: ma.TokenLiteral(),
+		Left:  ma.Left,
+		Value: ma.Value,
+	})
+}
+
 func (fp *FunctionParameter) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Type      string         `json:"type"`
@@ -194,10 +425,16 @@ func marshalExpression(exp Expression) (json.RawMessage, error) {
 		return json.Marshal(e)
 	case *IntegerLiteral:
 		return json.Marshal(e)
+	case *FloatLiteral:
+		return json.Marshal(e)
 	case *StringLiteral:
 		return json.Marshal(e)
+	case *BooleanLiteral:
+		return json.Marshal(e)
 	case *TypeLiteral:
 		return json.Marshal(e)
+	case *FunctionType:
+		return json.Marshal(e)
 	case *PrefixExpression:
 		return json.Marshal(e)
 	case *InfixExpression:
@@ -206,10 +443,38 @@ func marshalExpression(exp Expression) (json.RawMessage, error) {
 		return json.Marshal(e)
 	case *CallExpression:
 		return json.Marshal(e)
+	case *ConversionExpression:
+		return json.Marshal(e)
 	case *AssignmentExpression:
 		return json.Marshal(e)
+	case *MultiAssignmentExpression:
+		return json.Marshal(e)
 	case *FunctionParameter:
 		return json.Marshal(e)
+	case *ArrayType:
+		return json.Marshal(e)
+	case *ArrayLiteral:
+		return json.Marshal(e)
+	case *IndexExpression:
+		return json.Marshal(e)
+	case *SliceExpression:
+		return json.Marshal(e)
+	case *MapType:
+		return json.Marshal(e)
+	case *MapLiteral:
+		return json.Marshal(e)
+	case *KeyValue:
+		return json.Marshal(e)
+	case *StructType:
+		return json.Marshal(e)
+	case *StructField:
+		return json.Marshal(e)
+	case *StructLiteral:
+		return json.Marshal(e)
+	case *FieldValue:
+		return json.Marshal(e)
+	case *SelectorExpression:
+		return json.Marshal(e)
 	default:
 		return nil, fmt.Errorf("unknown expression type: %T", exp)
 	}
@@ -281,18 +546,51 @@ func (vs *VarStatement) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Type    string          `json:"type"`
 		Token   string          `json:"token_literal"`
+		Doc     string          `json:"doc,omitempty"`
 		Name    json.RawMessage `json:"name"`
 		VarType json.RawMessage `json:"var_type,omitempty"`
 		Value   json.RawMessage `json:"value"`
 	}{
 		Type:    "VarStatement",
 		Token:   vs.TokenLiteral(),
+		Doc:     vs.Doc,
 		Name:    nameJSON,
 		VarType: varTypeJSON,
 		Value:   valueJSON,
 	})
 }
 
+func (cs *ConstStatement) MarshalJSON() ([]byte, error) {
+	nameJSON, err := marshalExpression(cs.Name)
+	if err != nil {
+		return nil, err
+	}
+	valueJSON, err := marshalExpression(cs.Value)
+	if err != nil {
+		return nil, err
+	}
+	constTypeJSON, err := marshalExpression(cs.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Type      string          `json:"type"`
+		Token     string          `json:"token_literal"`
+		Doc       string          `json:"doc,omitempty"`
+		Name      json.RawMessage `json:"name"`
+		ConstType json.RawMessage `json:"const_type,omitempty"`
+		Value     json.RawMessage `json:"value"`
+	}{
+		Type:      "ConstStatement",
+		Token:     cs.TokenLiteral(),
+		Doc:       cs.Doc,
+		Name:      nameJSON,
+		ConstType: constTypeJSON,
+		Value:     valueJSON,
+	})
+}
+
 func (fd *FunctionDeclaration) MarshalJSON() ([]byte, error) {
 	nameJSON, err := marshalExpression(fd.Name)
 	if err != nil {
@@ -321,6 +619,7 @@ func (fd *FunctionDeclaration) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Type       string            `json:"type"`
 		Token      string            `json:"token_literal"`
+		Doc        string            `json:"doc,omitempty"`
 		Name       json.RawMessage   `json:"name"`
 		Parameters []json.RawMessage `json:"parameters"`
 		ReturnType json.RawMessage   `json:"return_type,omitempty"`
@@ -328,9 +627,157 @@ func (fd *FunctionDeclaration) MarshalJSON() ([]byte, error) {
 	}{
 		Type:       "FunctionDeclaration",
 		Token:      fd.TokenLiteral(),
+		Doc:        fd.Doc,
 		Name:       nameJSON,
 		Parameters: paramsJSON,
 		ReturnType: returnTypeJSON,
 		Body:       bodyJSON,
 	})
 }
+
+func (is *IfStatement) MarshalJSON() ([]byte, error) {
+	conditionJSON, err := marshalExpression(is.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	consequenceJSON, err := json.Marshal(is.Consequence)
+	if err != nil {
+		return nil, err
+	}
+
+	var alternativeJSON json.RawMessage
+	if is.Alternative != nil {
+		alternativeJSON, err = json.Marshal(is.Alternative)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(struct {
+		Type        string          `json:"type"`
+		Token       string          `json:"token_literal"`
+		Condition   json.RawMessage `json:"condition"`
+		Consequence json.RawMessage `json:"consequence"`
+		Alternative json.RawMessage `json:"alternative,omitempty"`
+	}{
+		Type:        "IfStatement",
+		Token:       is.TokenLiteral(),
+		Condition:   conditionJSON,
+		Consequence: consequenceJSON,
+		Alternative: alternativeJSON,
+	})
+}
+
+func (fs *ForStatement) MarshalJSON() ([]byte, error) {
+	var initJSON, postJSON json.RawMessage
+	var err error
+
+	if fs.Init != nil {
+		initJSON, err = json.Marshal(fs.Init)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	conditionJSON, err := marshalExpression(fs.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs.Post != nil {
+		postJSON, err = json.Marshal(fs.Post)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bodyJSON, err := json.Marshal(fs.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Type      string          `json:"type"`
+		Token     string          `json:"token_literal"`
+		Init      json.RawMessage `json:"init,omitempty"`
+		Condition json.RawMessage `json:"condition,omitempty"`
+		Post      json.RawMessage `json:"post,omitempty"`
+		Body      json.RawMessage `json:"body"`
+	}{
+		Type:      "ForStatement",
+		Token:     fs.TokenLiteral(),
+		Init:      initJSON,
+		Condition: conditionJSON,
+		Post:      postJSON,
+		Body:      bodyJSON,
+	})
+}
+
+func (bs *BreakStatement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string      `json:"type"`
+		Token string      `json:"token_literal"`
+		Label *Identifier `json:"label,omitempty"`
+	}{
+		Type:  "BreakStatement",
+		Token: bs.TokenLiteral(),
+		Label: bs.Label,
+	})
+}
+
+func (cs *ContinueStatement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string      `json:"type"`
+		Token string      `json:"token_literal"`
+		Label *Identifier `json:"label,omitempty"`
+	}{
+		Type:  "ContinueStatement",
+		Token: cs.TokenLiteral(),
+		Label: cs.Label,
+	})
+}
+
+func (ls *LabeledStatement) MarshalJSON() ([]byte, error) {
+	statementJSON, err := json.Marshal(ls.Statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Type      string          `json:"type"`
+		Token     string          `json:"token_literal"`
+		Label     *Identifier     `json:"label"`
+		Statement json.RawMessage `json:"statement"`
+	}{
+		Type:      "LabeledStatement",
+		Token:     ls.TokenLiteral(),
+		Label:     ls.Label,
+		Statement: statementJSON,
+	})
+}
+
+func (td *TypeDeclaration) MarshalJSON() ([]byte, error) {
+	nameJSON, err := marshalExpression(td.Name)
+	if err != nil {
+		return nil, err
+	}
+	declTypeJSON, err := marshalExpression(td.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Type     string          `json:"type"`
+		Token    string          `json:"token_literal"`
+		Doc      string          `json:"doc,omitempty"`
+		Name     json.RawMessage `json:"name"`
+		DeclType json.RawMessage `json:"decl_type"`
+	}{
+		Type:     "TypeDeclaration",
+		Token:    td.TokenLiteral(),
+		Doc:      td.Doc,
+		Name:     nameJSON,
+		DeclType: declTypeJSON,
+	})
+}
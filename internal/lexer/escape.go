@@ -0,0 +1,107 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Unquote decodes the raw source text of a string literal token, either
+// "interpreted" with escape sequences or `raw`.
+func Unquote(raw string) (string, error) {
+	runes := []rune(raw)
+	if len(runes) < 2 {
+		return "", errors.New("invalid string literal")
+	}
+
+	quote := runes[0]
+	if runes[len(runes)-1] != quote {
+		return "", errors.New("invalid string literal")
+	}
+	body := runes[1 : len(runes)-1]
+
+	switch quote {
+	case '`':
+		// Carriage returns are dropped so that raw strings do not depend
+		// on the line endings of the source file.
+		return strings.ReplaceAll(string(body), "\r", ""), nil
+	case '"':
+		var buff strings.Builder
+		for i := 0; i < len(body); {
+			if body[i] != '\\' {
+				buff.WriteRune(body[i])
+				i++
+				continue
+			}
+
+			r, n, err := decodeEscape(body, i)
+			if err != nil {
+				return "", err
+			}
+			buff.WriteRune(r)
+			i += n
+		}
+		return buff.String(), nil
+	default:
+		return "", errors.New("invalid string literal")
+	}
+}
+
+// decodeEscape decodes the escape sequence starting at s[i], which must be
+// a backslash. It returns the decoded rune and the number of runes the
+// sequence spans, which is also set on error so the caller can skip it.
+func decodeEscape(s []rune, i int) (rune, int, error) {
+	if i+1 >= len(s) {
+		return 0, 1, errors.New("escape sequence not terminated")
+	}
+
+	switch s[i+1] {
+	case 'n':
+		return '\n', 2, nil
+	case 't':
+		return '\t', 2, nil
+	case '\\':
+		return '\\', 2, nil
+	case '"':
+		return '"', 2, nil
+	case 'u':
+		return decodeUnicodeEscape(s, i)
+	default:
+		return 0, 2, fmt.Errorf("unknown escape sequence \\%c", s[i+1])
+	}
+}
+
+// decodeUnicodeEscape decodes a \u{XXXX} escape with one to six hex digits.
+func decodeUnicodeEscape(s []rune, i int) (rune, int, error) {
+	if i+2 >= len(s) || s[i+2] != '{' {
+		return 0, 2, errors.New("unicode escape must have the form \\u{XXXX}")
+	}
+
+	end := i + 3
+	for end < len(s) && s[end] != '}' && s[end] != '"' {
+		end++
+	}
+	if end >= len(s) || s[end] != '}' {
+		return 0, end - i, errors.New("unicode escape must have the form \\u{XXXX}")
+	}
+
+	n := end - i + 1
+	digits := string(s[i+3 : end])
+	if len(digits) == 0 || len(digits) > 6 {
+		return 0, n, fmt.Errorf("unicode escape \\u{%s} must have 1 to 6 hex digits", digits)
+	}
+
+	code, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return 0, n, fmt.Errorf("invalid hex digits in unicode escape \\u{%s}", digits)
+	}
+
+	r := rune(code)
+	if !utf8.ValidRune(r) {
+		return 0, n, fmt.Errorf("unicode escape \\u{%s} is not a valid code point", digits)
+	}
+
+	return r, n, nil
+}
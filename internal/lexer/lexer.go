@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"ixion/internal/token"
 )
@@ -11,13 +12,22 @@ import (
 type Lexer struct {
 	input         []rune
 	tokens        *token.Tokens
+	file          string
 	pos, row, col int
+	offset        int // byte offset of input[pos]
+	start         token.Pos
 }
 
 func New(in []rune) *Lexer {
+	return NewFile("", in)
+}
+
+// NewFile creates a lexer whose token positions refer to the named file.
+func NewFile(file string, in []rune) *Lexer {
 	return &Lexer{
 		input:  in,
 		tokens: token.NewTokens(),
+		file:   file,
 		pos:    0,
 		row:    1,
 		col:    1,
@@ -25,11 +35,7 @@ func New(in []rune) *Lexer {
 }
 
 func Tokenize(in string) (*token.Tokens, error) {
-	lexer := Lexer{
-		input:  []rune(in),
-		tokens: token.NewTokens(),
-	}
-	return lexer.Tokenize()
+	return New([]rune(in)).Tokenize()
 }
 
 func (l *Lexer) Tokenize() (*token.Tokens, error) {
@@ -41,17 +47,22 @@ LOOP:
 		l.skipWhiteSpace()
 
 		currentChar = l.peek(0)
+		l.start = l.position()
 
 		switch {
 		case unicode.IsLetter(currentChar):
 			l.tokenizeWord()
 		case currentChar == '\000':
 			break LOOP
+		case currentChar == '/' && (l.peek(1) == '/' || l.peek(1) == '*'):
+			if err := l.tokenizeComment(); err != nil {
+				return nil, err
+			}
 		case l.isOperator(currentChar):
-			tokenType, _ := token.IsOperator(currentChar)
-			l.makeToken(tokenType, string(currentChar))
-			currentChar = l.next()
-		case unicode.IsDigit(currentChar) || currentChar == '"':
+			if err := l.tokenizeOperator(); err != nil {
+				return nil, err
+			}
+		case unicode.IsDigit(currentChar) || currentChar == '"' || currentChar == '`':
 			if err := l.tokenizeLiteral(); err != nil {
 				return nil, err
 			}
@@ -60,6 +71,7 @@ LOOP:
 		}
 	}
 
+	l.start = l.position()
 	l.makeToken(token.EOF, token.EOF.String())
 
 	return l.tokens, nil
@@ -79,49 +91,264 @@ func (l *Lexer) tokenizeWord() {
 	if tokenType, ok := token.IsKeyword(word); ok {
 		l.makeToken(tokenType, tokenType.String())
 	} else if tokenType, ok := token.IsLangType(word); ok {
-		l.makeToken(tokenType, tokenType.String())
+		// Type tokens keep their spelling, it is what type literals display.
+		l.makeToken(tokenType, word)
 	} else {
 		l.makeToken(token.IDENT, word)
 	}
 }
 
 func (l *Lexer) tokenizeLiteral() error {
-	var buff strings.Builder
-
 	currentChar := l.peek(0)
 
 	switch {
 	case unicode.IsDigit(currentChar):
-		for unicode.IsDigit(currentChar) && currentChar != '\000' {
-			buff.WriteRune(currentChar)
-			currentChar = l.next()
-		}
-		l.makeToken(token.NUMBER_LITERAL, buff.String())
+		return l.tokenizeNumber()
 	case currentChar == '"':
-		currentChar = l.next()
-		for currentChar != '"' && currentChar != '\000' {
-			buff.WriteRune(currentChar)
-			currentChar = l.next()
+		return l.tokenizeString()
+	case currentChar == '`':
+		return l.tokenizeRawString()
+	}
+
+	return nil
+}
+
+// tokenizeNumber consumes a decimal, 0x hexadecimal, 0o octal or 0b binary
+// integer literal, or a decimal floating-point literal with an optional
+// exponent. Digits may be separated by underscores, as in 1_000_000. A
+// decimal integer literal other than 0 must not start with 0.
+func (l *Lexer) tokenizeNumber() error {
+	begin := l.pos
+	tokenType := token.NUMBER_LITERAL
+	isDigit := isDecimal
+	base := "decimal"
+
+	if l.peek(0) == '0' {
+		switch unicode.ToLower(l.peek(1)) {
+		case 'x':
+			tokenType, isDigit, base = token.HEX_LITERAL, isHex, "hexadecimal"
+		case 'o':
+			tokenType, isDigit, base = token.OCTAL_LITERAL, isOctal, "octal"
+		case 'b':
+			tokenType, isDigit, base = token.BINARY_LITERAL, isBinary, "binary"
+		}
+	}
+
+	if tokenType != token.NUMBER_LITERAL {
+		l.incPos()
+		l.incPos()
+		if l.scanDigits(isDigit) == 0 {
+			return l.createError(InvalidNumberLiteral, base+" literal has no digits")
 		}
+	} else {
+		l.scanDigits(isDigit)
 
-		if currentChar == '\000' {
+		if l.peek(0) == '.' && isDecimal(l.peek(1)) {
+			tokenType = token.FLOAT_LITERAL
+			l.incPos()
+			l.scanDigits(isDecimal)
+		}
+
+		if unicode.ToLower(l.peek(0)) == 'e' {
+			sign := 0
+			if l.peek(1) == '+' || l.peek(1) == '-' {
+				sign = 1
+			}
+			if isDecimal(l.peek(1 + sign)) {
+				tokenType = token.FLOAT_LITERAL
+				for range 1 + sign {
+					l.incPos()
+				}
+				l.scanDigits(isDecimal)
+			}
+		}
+	}
+
+	if c := l.peek(0); unicode.IsLetter(c) || unicode.IsDigit(c) {
+		l.incPos()
+		return l.createError(InvalidNumberLiteral, fmt.Sprintf("invalid character %q in %s literal", c, base))
+	}
+
+	text := string(l.input[begin:l.pos])
+	if !validSeparators(text, isDigit) {
+		return l.createError(InvalidNumberLiteral, "'_' must separate successive digits")
+	}
+
+	// Octal literals need the 0o prefix; a leading zero is not one.
+	if tokenType == token.NUMBER_LITERAL && len(text) > 1 && text[0] == '0' {
+		return l.createError(InvalidNumberLiteral, "decimal literal must not have a leading zero")
+	}
+
+	l.makeToken(tokenType, text)
+	return nil
+}
+
+// scanDigits consumes digits and '_' separators and returns the number of
+// digits consumed.
+func (l *Lexer) scanDigits(isDigit func(rune) bool) int {
+	count := 0
+	for c := l.peek(0); isDigit(c) || c == '_'; c = l.peek(0) {
+		if c != '_' {
+			count++
+		}
+		l.incPos()
+	}
+	return count
+}
+
+// validSeparators reports whether every '_' in a number literal sits
+// between two digits, or between a base prefix and a digit.
+func validSeparators(text string, isDigit func(rune) bool) bool {
+	runes := []rune(text)
+	for i, c := range runes {
+		if c != '_' {
+			continue
+		}
+
+		if i+1 >= len(runes) || !isDigit(runes[i+1]) {
+			return false
+		}
+
+		prev := runes[i-1]
+		if !isDigit(prev) && !(i == 2 && runes[0] == '0' && strings.ContainsRune("xXoObB", prev)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDecimal(c rune) bool { return '0' <= c && c <= '9' }
+func isOctal(c rune) bool   { return '0' <= c && c <= '7' }
+func isBinary(c rune) bool  { return c == '0' || c == '1' }
+func isHex(c rune) bool {
+	return isDecimal(c) || 'a' <= unicode.ToLower(c) && unicode.ToLower(c) <= 'f'
+}
+
+// tokenizeString consumes an interpreted string literal. The token keeps
+// the raw source text, quotes and escape sequences included; escape
+// sequences are only validated here and decoded by Unquote.
+func (l *Lexer) tokenizeString() error {
+	begin := l.pos
+	l.incPos() // opening quote
+
+	for {
+		switch currentChar := l.peek(0); {
+		case l.pos >= len(l.input):
 			return l.createError(UnclosedStringLiteral, "string literal must be closed")
+		case currentChar == '\n':
+			return l.createError(UnclosedStringLiteral, "newline in string literal")
+		case currentChar == '"':
+			l.incPos()
+			l.makeToken(token.STRING_LITERAL, string(l.input[begin:l.pos]))
+			return nil
+		case currentChar == '\\':
+			escStart := l.position()
+			_, n, err := decodeEscape(l.input, l.pos)
+			for range n {
+				l.incPos()
+			}
+			if err != nil {
+				return l.errorFrom(escStart, InvalidEscape, err.Error())
+			}
+		default:
+			l.incPos()
 		}
+	}
+}
+
+// tokenizeRawString consumes a `raw` string literal, which has no escape
+// sequences and may span several lines.
+func (l *Lexer) tokenizeRawString() error {
+	begin := l.pos
+	l.incPos() // opening backtick
 
+	for l.pos < len(l.input) && l.peek(0) != '`' {
 		l.incPos()
-		l.makeToken(token.STRING_LITERAL, buff.String())
 	}
 
+	if l.pos >= len(l.input) {
+		return l.createError(UnclosedStringLiteral, "raw string literal must be closed")
+	}
+
+	l.incPos()
+	l.makeToken(token.STRING_LITERAL, string(l.input[begin:l.pos]))
 	return nil
 }
 
+// tokenizeComment consumes a "//" line comment or a "/* */" block comment.
+// Block comments may be nested.
+func (l *Lexer) tokenizeComment() error {
+	begin := l.pos
+
+	if l.peek(1) == '/' {
+		for l.pos < len(l.input) && l.peek(0) != '\n' {
+			l.incPos()
+		}
+		l.makeToken(token.COMMENT, string(l.input[begin:l.pos]))
+		return nil
+	}
+
+	depth := 0
+	for l.pos < len(l.input) {
+		switch {
+		case l.peek(0) == '/' && l.peek(1) == '*':
+			depth++
+			l.incPos()
+		case l.peek(0) == '*' && l.peek(1) == '/':
+			depth--
+			l.incPos()
+		}
+		l.incPos()
+
+		if depth == 0 {
+			l.makeToken(token.COMMENT, string(l.input[begin:l.pos]))
+			return nil
+		}
+	}
+
+	return l.createError(UnclosedComment, "block comment must be closed")
+}
+
+// tokenizeOperator consumes the longest operator starting at the current
+// position.
+func (l *Lexer) tokenizeOperator() error {
+	for size := token.MaxOperatorLen; size > 0; size-- {
+		if l.pos+size > len(l.input) {
+			continue
+		}
+
+		text := string(l.input[l.pos : l.pos+size])
+		if tokenType, ok := token.LookupOperator(text); ok {
+			for range size {
+				l.incPos()
+			}
+			l.makeToken(tokenType, text)
+			return nil
+		}
+	}
+
+	l.incPos()
+	return l.createError(InvalidOperator, string(l.input[l.pos-1]))
+}
+
 func (l *Lexer) isOperator(char rune) bool {
-	_, ok := token.IsOperator(char)
-	return ok
+	return token.IsOperatorStart(char)
 }
 
+// makeToken appends a token spanning from the start of the current token
+// up to the current position.
 func (l *Lexer) makeToken(_type token.TokenType, text string) {
-	l.tokens.Append(token.New(_type, text))
+	l.tokens.Append(token.NewAt(_type, text, token.NewSpan(l.start, l.position())))
+}
+
+func (l *Lexer) position() token.Pos {
+	return token.Pos{
+		File:   l.file,
+		Offset: l.offset,
+		Line:   l.row,
+		Column: l.col,
+	}
 }
 
 func (l *Lexer) skipWhiteSpace() {
@@ -150,6 +377,7 @@ func (l *Lexer) incPos() {
 	} else {
 		l.col++
 	}
+	l.offset += utf8.RuneLen(l.input[l.pos])
 	l.pos++
 }
 
@@ -161,6 +389,19 @@ func (l *Lexer) peek(currentPos int) rune {
 	return l.input[finalPos]
 }
 
+// createError reports an error spanning from the start of the current token
+// up to the current position.
 func (l *Lexer) createError(kind LexerErrorKind, msg string) error {
-	return newError(kind, fmt.Sprintf("%d:%d", l.row, l.col), msg)
+	return l.errorFrom(l.start, kind, msg)
+}
+
+// errorFrom reports an error spanning from start up to the current
+// position, or covering the current rune if nothing was consumed yet.
+func (l *Lexer) errorFrom(start token.Pos, kind LexerErrorKind, msg string) error {
+	end := l.position()
+	if end.Offset == start.Offset && l.pos < len(l.input) {
+		end.Offset += utf8.RuneLen(l.input[l.pos])
+		end.Column++
+	}
+	return newError(kind, token.NewSpan(start, end), msg)
 }
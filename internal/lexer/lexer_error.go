@@ -1,6 +1,10 @@
 package lexer
 
-import "fmt"
+import (
+	"fmt"
+
+	"ixion/internal/token"
+)
 
 type LexerErrorKind int
 
@@ -10,6 +14,9 @@ const (
 	InvalidOperator
 	UnexpectedCharacter
 	UnclosedStringLiteral
+	UnclosedComment
+	InvalidEscape
+	InvalidNumberLiteral
 )
 
 var kinds = map[LexerErrorKind]string{
@@ -17,6 +24,9 @@ var kinds = map[LexerErrorKind]string{
 	InvalidOperator:       "Invalid Operator",
 	UnexpectedCharacter:   "Unexpected Character",
 	UnclosedStringLiteral: "Unclosed String Literal",
+	UnclosedComment:       "Unclosed Comment",
+	InvalidEscape:         "Invalid Escape Sequence",
+	InvalidNumberLiteral:  "Invalid Number Literal",
 }
 
 func (k LexerErrorKind) String() string {
@@ -25,21 +35,26 @@ func (k LexerErrorKind) String() string {
 
 type LexerError struct {
 	Kind    LexerErrorKind
-	Pos     string
+	Span    token.Span
 	Message string
 }
 
-func newError(kind LexerErrorKind, pos string, msg string) error {
+func newError(kind LexerErrorKind, span token.Span, msg string) error {
 	return &LexerError{
 		Kind:    kind,
-		Pos:     pos,
+		Span:    span,
 		Message: msg,
 	}
 }
 
+// Pos returns the position the error starts at.
+func (l *LexerError) Pos() token.Pos {
+	return l.Span.Start
+}
+
 func (l *LexerError) Error() string {
 	if l.Message == "" {
-		return fmt.Sprintf("lexer error at %s: %s", l.Pos, l.Kind.String())
+		return fmt.Sprintf("lexer error at %s: %s", l.Span.Start, l.Kind.String())
 	}
-	return fmt.Sprintf("lexer error at %s: %s: %s", l.Pos, l.Kind.String(), l.Message)
+	return fmt.Sprintf("lexer error at %s: %s: %s", l.Span.Start, l.Kind.String(), l.Message)
 }
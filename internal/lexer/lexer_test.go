@@ -9,6 +9,16 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// stripSpans drops source positions so that tests can compare
+// token types and texts only.
+func stripSpans(toks []token.Token) []token.Token {
+	out := make([]token.Token, len(toks))
+	for i, tok := range toks {
+		out[i] = token.New(tok.Type, tok.Text)
+	}
+	return out
+}
+
 func TestLexer_Tokinaze(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -26,8 +36,157 @@ func TestLexer_Tokinaze(t *testing.T) {
 				token.New(token.ASSIGN, string('=')),
 				token.New(token.NUMBER_LITERAL, "1"),
 				token.New(token.SEMICOLON, string(';')),
+				token.New(token.EOF, token.EOF.String()),
+			},
+		},
+		{
+			name:  "comparison operators",
+			input: "a<=b == c!=d>=e<f>g",
+			want: []token.Token{
+				token.New(token.IDENT, "a"),
+				token.New(token.LT_EQ, "<="),
+				token.New(token.IDENT, "b"),
+				token.New(token.EQ, "=="),
+				token.New(token.IDENT, "c"),
+				token.New(token.NOT_EQ, "!="),
+				token.New(token.IDENT, "d"),
+				token.New(token.GT_EQ, ">="),
+				token.New(token.IDENT, "e"),
+				token.New(token.LT, "<"),
+				token.New(token.IDENT, "f"),
+				token.New(token.GT, ">"),
+				token.New(token.IDENT, "g"),
+				token.New(token.EOF, token.EOF.String()),
+			},
+		},
+		{
+			name:  "boolean operators",
+			input: "!a && b || true",
+			want: []token.Token{
+				token.New(token.BANG, "!"),
+				token.New(token.IDENT, "a"),
+				token.New(token.AND, "&&"),
+				token.New(token.IDENT, "b"),
+				token.New(token.OR, "||"),
+				token.New(token.TRUE, token.TRUE.String()),
+				token.New(token.EOF, token.EOF.String()),
+			},
+		},
+		{
+			name:    "single ampersand",
+			input:   "a & b",
+			wantErr: true,
+			errText: "lexer error at 1:3: Invalid Operator: &",
+		},
+		{
+			name:  "comments",
+			input: "a / b // line\n/* block /* nested */ */ c",
+			want: []token.Token{
+				token.New(token.IDENT, "a"),
+				token.New(token.DIV, "/"),
+				token.New(token.IDENT, "b"),
+				token.New(token.COMMENT, "// line"),
+				token.New(token.COMMENT, "/* block /* nested */ */"),
+				token.New(token.IDENT, "c"),
+				token.New(token.EOF, token.EOF.String()),
+			},
+		},
+		{
+			name:    "unclosed block comment",
+			input:   "a /* b /* c */",
+			wantErr: true,
+			errText: "lexer error at 1:3: Unclosed Comment: block comment must be closed",
+		},
+		{
+			name:  "strings keep their raw text",
+			input: "\"a\\n\\\"b\\\"\" `raw\nline`",
+			want: []token.Token{
+				token.New(token.STRING_LITERAL, `"a\n\"b\""`),
+				token.New(token.STRING_LITERAL, "`raw\nline`"),
+				token.New(token.EOF, token.EOF.String()),
+			},
+		},
+		{
+			name:    "invalid escape",
+			input:   `var a = "x\q";`,
+			wantErr: true,
+			errText: "lexer error at 1:11: Invalid Escape Sequence: unknown escape sequence \\q",
+		},
+		{
+			name:    "invalid unicode escape",
+			input:   `var a = "\u{D800}";`,
+			wantErr: true,
+			errText: "lexer error at 1:10: Invalid Escape Sequence: unicode escape \\u{D800} is not a valid code point",
+		},
+		{
+			name:    "newline in string",
+			input:   "var a = \"x\ny\";",
+			wantErr: true,
+			errText: "lexer error at 1:9: Unclosed String Literal: newline in string literal",
+		},
+		{
+			name:    "unclosed raw string",
+			input:   "var a = `x",
+			wantErr: true,
+			errText: "lexer error at 1:9: Unclosed String Literal: raw string literal must be closed",
+		},
+		{
+			name:  "numeric literals",
+			input: "42 0xFF 0o17 0b1010 1_000_000 1.5 2.5e-3 1E10",
+			want: []token.Token{
+				token.New(token.NUMBER_LITERAL, "42"),
+				token.New(token.HEX_LITERAL, "0xFF"),
+				token.New(token.OCTAL_LITERAL, "0o17"),
+				token.New(token.BINARY_LITERAL, "0b1010"),
+				token.New(token.NUMBER_LITERAL, "1_000_000"),
+				token.New(token.FLOAT_LITERAL, "1.5"),
+				token.New(token.FLOAT_LITERAL, "2.5e-3"),
+				token.New(token.FLOAT_LITERAL, "1E10"),
+				token.New(token.EOF, token.EOF.String()),
 			},
 		},
+		{
+			name:    "invalid binary digit",
+			input:   "var a = 0b102;",
+			wantErr: true,
+			errText: "lexer error at 1:9: Invalid Number Literal: invalid character '2' in binary literal",
+		},
+		{
+			name:    "misplaced separator",
+			input:   "var a = 1__000;",
+			wantErr: true,
+			errText: "lexer error at 1:9: Invalid Number Literal: '_' must separate successive digits",
+		},
+		{
+			name:    "leading zero",
+			input:   "var a = 010 + 120;",
+			wantErr: true,
+			errText: "lexer error at 1:9: Invalid Number Literal: decimal literal must not have a leading zero",
+		},
+		{
+			name:    "leading zero with non-octal digit",
+			input:   "var a = 09;",
+			wantErr: true,
+			errText: "lexer error at 1:9: Invalid Number Literal: decimal literal must not have a leading zero",
+		},
+		{
+			name:    "prefix without digits",
+			input:   "var a = 0x;",
+			wantErr: true,
+			errText: "lexer error at 1:9: Invalid Number Literal: hexadecimal literal has no digits",
+		},
+		{
+			name:    "unclosed string",
+			input:   "var a = \"abc",
+			wantErr: true,
+			errText: "lexer error at 1:9: Unclosed String Literal: string literal must be closed",
+		},
+		{
+			name:    "unexpected character",
+			input:   "var a = 1 $",
+			wantErr: true,
+			errText: "lexer error at 1:11: Unexpected Character: $",
+		},
 	}
 
 	for _, tt := range testCases {
@@ -40,11 +199,57 @@ func TestLexer_Tokinaze(t *testing.T) {
 				assert.Error(t, err, "want a nil error, has: ", err)
 
 				assert.EqualError(t, err, tt.errText)
+				return
 			} else {
 				require.NoError(t, err, "want a non nil error")
 			}
 
-			assert.Equal(t, tt.want, got.Reset())
+			assert.Equal(t, tt.want, stripSpans(got.Reset()))
+		})
+	}
+}
+
+func TestLexer_Positions(t *testing.T) {
+	l := lexer.NewFile("main.ix", []rune("var ä = 1;\n  print(ä);"))
+
+	got, err := l.Tokenize()
+	require.NoError(t, err)
+
+	toks := got.Reset()
+	require.Len(t, toks, 11)
+
+	// 'ä' is two bytes long, so byte offsets and columns diverge.
+	assert.Equal(t, token.NewSpan(
+		token.Pos{File: "main.ix", Offset: 4, Line: 1, Column: 5},
+		token.Pos{File: "main.ix", Offset: 6, Line: 1, Column: 6},
+	), toks[1].Span)
+
+	assert.Equal(t, token.NewSpan(
+		token.Pos{File: "main.ix", Offset: 10, Line: 1, Column: 10},
+		token.Pos{File: "main.ix", Offset: 11, Line: 1, Column: 11},
+	), toks[4].Span)
+
+	assert.Equal(t, token.Pos{File: "main.ix", Offset: 14, Line: 2, Column: 3}, toks[5].Pos())
+	assert.Equal(t, "main.ix:2:9", toks[7].Pos().String())
+}
+
+func TestUnquote(t *testing.T) {
+	testCases := []struct {
+		raw  string
+		want string
+	}{
+		{`"plain"`, "plain"},
+		{`"a\tb\nc"`, "a\tb\nc"},
+		{`"\"quoted\" \\"`, `"quoted" \`},
+		{`"\u{41}\u{1F600}"`, "A\U0001F600"},
+		{"`raw \\n\r\nline`", "raw \\n\nline"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := lexer.Unquote(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"strings"
+
+	"ixion/internal/token"
+)
+
+// docText returns the text of a comment group if it ends on the line
+// directly above (or on the same line as) tok, and "" otherwise.
+func docText(group []token.Token, tok token.Token) string {
+	if len(group) == 0 {
+		return ""
+	}
+
+	if group[len(group)-1].Span.End.Line < tok.Span.Start.Line-1 {
+		return ""
+	}
+
+	var lines []string
+	for _, comment := range group {
+		lines = append(lines, commentLines(comment.Text)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// commentLines strips the comment markers from a raw comment and returns
+// its lines.
+func commentLines(raw string) []string {
+	if text, ok := strings.CutPrefix(raw, "//"); ok {
+		return []string{strings.TrimPrefix(text, " ")}
+	}
+
+	text := strings.TrimSuffix(strings.TrimPrefix(raw, "/*"), "*/")
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "* ")
+		if line == "*" {
+			line = ""
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -1,10 +1,13 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"ixion/internal/ast"
+	"ixion/internal/lexer"
 	"ixion/internal/token"
 )
 
@@ -13,6 +16,8 @@ const (
 	_ int = iota
 	LOWEST
 	ASSIGN      // =
+	LOGICAL_OR  // ||
+	LOGICAL_AND // &&
 	EQUALS      // ==
 	LESSGREATER // > or <
 	SUM         // +
@@ -22,12 +27,24 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
-	token.ASSIGN:    ASSIGN,
-	token.PLUS:      SUM,
-	token.MINUS:     SUM,
-	token.DIV:       PRODUCT,
-	token.MUL:       PRODUCT,
-	token.LPAREN:    CALL,
+	token.ASSIGN: ASSIGN,
+	token.OR:     LOGICAL_OR,
+	token.AND:    LOGICAL_AND,
+	token.EQ:     EQUALS,
+	token.NOT_EQ: EQUALS,
+	token.LT:     LESSGREATER,
+	token.GT:     LESSGREATER,
+	token.LT_EQ:  LESSGREATER,
+	token.GT_EQ:  LESSGREATER,
+	token.PLUS:   SUM,
+	token.MINUS:  SUM,
+	token.DIV:    PRODUCT,
+	token.MUL:    PRODUCT,
+	token.LPAREN: CALL,
+	token.LBRACE: CALL,
+	token.DOT:    CALL,
+
+	token.LBRACKET: CALL,
 }
 
 type (
@@ -42,7 +59,16 @@ type Parser struct {
 	curToken   token.Token
 	peekToken  token.Token
 
-	errors []string
+	// Doc comments directly preceding curToken and peekToken.
+	curDoc  string
+	peekDoc string
+
+	errors []error
+
+	// noStructLiteral is set while parsing the header of an if or for
+	// statement, where '{' after a name opens the body rather than a
+	// struct literal.
+	noStructLiteral bool
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
@@ -51,24 +77,51 @@ type Parser struct {
 func New(tokens *token.Tokens) *Parser {
 	p := &Parser{
 		tokens: tokens,
-		errors: []string{},
+		errors: []error{},
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.NUMBER_LITERAL, p.parseIntegerLiteral)
+	p.registerPrefix(token.HEX_LITERAL, p.parseIntegerLiteral)
+	p.registerPrefix(token.OCTAL_LITERAL, p.parseIntegerLiteral)
+	p.registerPrefix(token.BINARY_LITERAL, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT_LITERAL, p.parseFloatLiteral)
 	p.registerPrefix(token.STRING_LITERAL, p.parseStringLiteral)
+	p.registerPrefix(token.TRUE, p.parseBooleanLiteral)
+	p.registerPrefix(token.FALSE, p.parseBooleanLiteral)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.FN, p.parseFunctionLiteral)
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(token.LBRACE, p.parseMapLiteral)
+	for _, t := range []token.TokenType{
+		token.INT, token.INT8, token.INT16, token.INT32, token.INT64,
+		token.UINT, token.UINT8, token.UINT16, token.UINT32, token.UINT64,
+		token.FLOAT, token.STRING, token.BOOL,
+	} {
+		p.registerPrefix(t, p.parseConversionExpression)
+	}
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.DIV, p.parseInfixExpression)
 	p.registerInfix(token.MUL, p.parseInfixExpression)
+	p.registerInfix(token.EQ, p.parseInfixExpression)
+	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.LT, p.parseInfixExpression)
+	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.ASSIGN, p.parseAssignmentExpression)
+	p.registerInfix(token.LBRACE, p.parseStructLiteral)
+	p.registerInfix(token.DOT, p.parseSelectorExpression)
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 
 	// Read two tokens, so curToken and peekToken are both set
 	p.nextToken()
@@ -77,24 +130,79 @@ func New(tokens *token.Tokens) *Parser {
 	return p
 }
 
-func (p *Parser) Errors() []string {
+func (p *Parser) Errors() []error {
 	return p.errors
 }
 
+func (p *Parser) errorf(span token.Span, format string, args ...any) {
+	p.errors = append(p.errors, newError(span, fmt.Sprintf(format, args...)))
+}
+
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
+	p.errorf(p.peekToken.Span, "expected next token to be %s, got %s instead",
 		t.String(), p.peekToken.Type.String())
-	p.errors = append(p.errors, msg)
 }
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
+	p.curDoc = p.peekDoc
+	p.peekToken, p.peekDoc = p.readToken()
+}
+
+// readToken returns the next non-comment token together with the doc
+// comment directly preceding it.
+func (p *Parser) readToken() (token.Token, string) {
+	var group []token.Token
+
+	for {
+		tok := p.rawToken()
+		if !tok.Type.Is(token.COMMENT) {
+			return tok, docText(group, tok)
+		}
+
+		// A comment on the same line as the previous token trails it
+		// and does not document what follows.
+		if len(group) == 0 && p.curToken.Span.IsValid() &&
+			p.curToken.Span.End.Line == tok.Span.Start.Line {
+			continue
+		}
+
+		// A blank line ends the comment group.
+		if len(group) > 0 && tok.Span.Start.Line > group[len(group)-1].Span.End.Line+1 {
+			group = group[:0]
+		}
+
+		group = append(group, tok)
+	}
+}
+
+func (p *Parser) rawToken() token.Token {
+	var tok token.Token
 	if p.currentPos < len(*p.tokens) {
-		p.peekToken = (*p.tokens)[p.currentPos]
+		tok = (*p.tokens)[p.currentPos]
 	} else {
-		p.peekToken = token.New(token.EOF, "")
+		end := p.curToken.Span.End
+		tok = token.NewAt(token.EOF, "", token.NewSpan(end, end))
 	}
 	p.currentPos++
+
+	return tok
+}
+
+// spanFrom returns the span from the start of the given token up to the end
+// of the current token.
+func (p *Parser) spanFrom(start token.Token) token.Span {
+	return token.NewSpan(start.Span.Start, p.curToken.Span.End)
+}
+
+// spanFromNode returns the span from the start of the given node up to the
+// end of the current token. A nil node, left behind by an earlier parse
+// error, yields the current token's span.
+func (p *Parser) spanFromNode(start ast.Node) token.Span {
+	if start == nil {
+		return p.curToken.Span
+	}
+	return token.NewSpan(start.Span().Start, p.curToken.Span.End)
 }
 
 func (p *Parser) ParseProgram() *ast.Program {
@@ -117,17 +225,46 @@ func (p *Parser) parseStatement() ast.Statement {
 	case token.VAR:
 		return p.parseVarStatement()
 	case token.CONST:
-		// TODO: Implement parseConstStatement
-		return nil
+		return p.parseConstStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
 	case token.PRINT:
 		return p.parsePrintStatement()
+	case token.IF:
+		if stmt := p.parseIfStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.FOR:
+		if stmt := p.parseForStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
+	case token.TYPE:
+		if stmt := p.parseTypeDeclaration(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.IDENT:
+		if p.peekTokenIs(token.COLON) {
+			if stmt := p.parseLabeledStatement(); stmt != nil {
+				return stmt
+			}
+			return nil
+		}
+		return p.parseExpressionStatement()
 	case token.FN:
 		// This could be a function declaration or a function literal assigned to a variable.
 		// For now, assume it's a function declaration if followed by an identifier.
 		if p.peekTokenIs(token.IDENT) {
-			return p.parseFunctionDeclaration()
+			if stmt := p.parseFunctionDeclaration(); stmt != nil {
+				return stmt
+			}
+			return nil
 		}
 		// If not a declaration, it will be handled as an expression statement
 		fallthrough
@@ -136,25 +273,55 @@ func (p *Parser) parseStatement() ast.Statement {
 	}
 }
 
+func (p *Parser) parseTypeDeclaration() *ast.TypeDeclaration {
+	stmt := &ast.TypeDeclaration{Token: p.curToken, Doc: p.curDoc}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = p.newIdentifier()
+
+	p.nextToken() // Advance to the start of the type
+	if stmt.Type = p.parseType(); stmt.Type == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
 func (p *Parser) parseVarStatement() *ast.VarStatement {
-	stmt := &ast.VarStatement{Token: p.curToken}
+	stmt := p.parseVarDeclaration()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
+func (p *Parser) parseConstStatement() *ast.ConstStatement {
+	stmt := &ast.ConstStatement{Token: p.curToken, Doc: p.curDoc}
 
 	if !p.expectPeek(token.IDENT) {
-		// Error already added by expectPeek
-		return stmt // Return partially constructed statement to avoid nil panic
+		return stmt
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Text}
+	stmt.Name = p.newIdentifier()
 
 	// Optional type annotation
-	if p.peekToken.IsType() {
-		p.nextToken() // Advance to the type token
-		stmt.Type = &ast.TypeLiteral{Token: p.curToken, Value: p.curToken.Text}
+	if p.peekIsType() {
+		p.nextToken() // Advance to the start of the type
+		stmt.Type = p.parseType()
 	}
 
 	if !p.expectPeek(token.ASSIGN) {
-		// Error already added by expectPeek
-		return stmt // Return partially constructed statement to avoid nil panic
+		return stmt
 	}
 
 	p.nextToken() // Advance past ASSIGN
@@ -165,20 +332,62 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 		p.nextToken()
 	}
 
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
+// parseVarDeclaration parses a var statement up to, but not including,
+// its terminating semicolon.
+func (p *Parser) parseVarDeclaration() *ast.VarStatement {
+	stmt := &ast.VarStatement{Token: p.curToken, Doc: p.curDoc}
+
+	if !p.expectPeek(token.IDENT) {
+		// Error already added by expectPeek
+		return stmt // Return partially constructed statement to avoid nil panic
+	}
+
+	stmt.Name = p.newIdentifier()
+
+	// Optional type annotation
+	if p.peekIsType() {
+		p.nextToken() // Advance to the start of the type
+		stmt.Type = p.parseType()
+	}
+
+	// A variable with a type may be declared without an initializer and
+	// assigned later.
+	if stmt.Type != nil && !p.peekTokenIs(token.ASSIGN) {
+		stmt.Loc = p.spanFrom(stmt.Token)
+		return stmt
+	}
+
+	if !p.expectPeek(token.ASSIGN) {
+		// Error already added by expectPeek
+		return stmt // Return partially constructed statement to avoid nil panic
+	}
+
+	p.nextToken() // Advance past ASSIGN
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	stmt.Loc = p.spanFrom(stmt.Token)
 	return stmt
 }
 
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
-	p.nextToken() // Advance past RETURN
-
-	stmt.ReturnValue = p.parseExpression(LOWEST)
+	// A bare return has no value.
+	if !p.peekTokenIs(token.SEMICOLON) && !p.peekTokenIs(token.RBRACE) && !p.peekTokenIs(token.EOF) {
+		p.nextToken() // Advance past RETURN
+		stmt.ReturnValue = p.parseExpression(LOWEST)
+	}
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
+	stmt.Loc = p.spanFrom(stmt.Token)
 	return stmt
 }
 
@@ -201,6 +410,7 @@ func (p *Parser) parsePrintStatement() *ast.PrintStatement {
 		p.nextToken()
 	}
 
+	stmt.Loc = p.spanFrom(stmt.Token)
 	return stmt
 }
 
@@ -208,17 +418,22 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 
 	stmt.Expression = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.COMMA) {
+		stmt.Expression = p.parseMultiAssignment(stmt.Expression)
+	}
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
+	stmt.Loc = p.spanFrom(stmt.Token)
 	return stmt
 }
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
+	defer p.structLiterals(true)()
 
 	p.nextToken() // Advance past LBRACE
 
@@ -231,20 +446,200 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	}
 
 	if !p.curTokenIs(token.RBRACE) {
-		p.errors = append(p.errors, "expected '}' at end of block statement")
+		p.errorf(p.spanFrom(block.Token), "expected '}' at end of block statement")
 		return nil
 	}
 
+	block.Loc = p.spanFrom(block.Token)
 	return block
 }
 
+func (p *Parser) parseIfStatement() *ast.IfStatement {
+	stmt := &ast.IfStatement{Token: p.curToken}
+	defer p.structLiterals(false)()
+
+	p.nextToken() // Advance past IF
+
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Consequence = p.parseBlockStatement()
+	if stmt.Consequence == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.ELSE) {
+		p.nextToken() // Advance to ELSE
+
+		switch {
+		case p.peekTokenIs(token.IF):
+			p.nextToken() // Advance to IF
+			alternative := p.parseIfStatement()
+			if alternative == nil {
+				return nil
+			}
+			stmt.Alternative = alternative
+		case p.expectPeek(token.LBRACE):
+			alternative := p.parseBlockStatement()
+			if alternative == nil {
+				return nil
+			}
+			stmt.Alternative = alternative
+		default:
+			return nil
+		}
+	}
+
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
+// parseForStatement parses the three loop forms:
+//
+//	for { ... }
+//	for cond { ... }
+//	for init; cond; post { ... }
+func (p *Parser) parseForStatement() *ast.ForStatement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+	defer p.structLiterals(false)()
+
+	if !p.peekTokenIs(token.LBRACE) {
+		p.nextToken() // Advance past FOR
+
+		var init ast.Statement
+		if !p.curTokenIs(token.SEMICOLON) {
+			init = p.parseSimpleStatement()
+
+			if !p.peekTokenIs(token.SEMICOLON) {
+				// for cond { ... }
+				es, ok := init.(*ast.ExpressionStatement)
+				if !ok {
+					p.errorf(init.Span(), "expected for loop condition, got %s", init.TokenLiteral())
+					return nil
+				}
+				stmt.Condition = es.Expression
+				init = nil
+			} else {
+				p.nextToken() // Advance to the SEMICOLON after init
+			}
+		}
+
+		if p.curTokenIs(token.SEMICOLON) {
+			stmt.Init = init
+
+			if !p.peekTokenIs(token.SEMICOLON) {
+				p.nextToken() // Advance to the condition
+				stmt.Condition = p.parseExpression(LOWEST)
+			}
+
+			if !p.expectPeek(token.SEMICOLON) {
+				return nil
+			}
+
+			if !p.peekTokenIs(token.LBRACE) {
+				p.nextToken() // Advance to the post statement
+				if p.curTokenIs(token.VAR) {
+					p.errorf(p.curToken.Span, "cannot declare variables in for loop post statement")
+					return nil
+				}
+				stmt.Post = p.parseSimpleStatement()
+			}
+		}
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+	if stmt.Body == nil {
+		return nil
+	}
+
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
+// parseSimpleStatement parses a var declaration or an expression statement
+// as it appears in a for clause. Unlike parseStatement it leaves the
+// terminating semicolon unconsumed.
+func (p *Parser) parseSimpleStatement() ast.Statement {
+	if p.curTokenIs(token.VAR) {
+		return p.parseVarDeclaration()
+	}
+
+	stmt := &ast.ExpressionStatement{Token: p.curToken}
+	stmt.Expression = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.COMMA) {
+		stmt.Expression = p.parseMultiAssignment(stmt.Expression)
+	}
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.IDENT) {
+		p.nextToken() // Advance to the label
+		stmt.Label = p.newIdentifier()
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.IDENT) {
+		p.nextToken() // Advance to the label
+		stmt.Label = p.newIdentifier()
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
+func (p *Parser) parseLabeledStatement() *ast.LabeledStatement {
+	stmt := &ast.LabeledStatement{Token: p.curToken, Label: p.newIdentifier()}
+
+	p.nextToken() // Advance to COLON
+
+	if p.peekTokenIs(token.RBRACE) || p.peekTokenIs(token.EOF) {
+		p.errorf(p.spanFrom(stmt.Token), "label '%s' must be followed by a statement", stmt.Label.Value)
+		return nil
+	}
+
+	p.nextToken() // Advance past COLON
+
+	stmt.Statement = p.parseStatement()
+	if stmt.Statement == nil {
+		return nil
+	}
+
+	stmt.Loc = p.spanFrom(stmt.Token)
+	return stmt
+}
+
 func (p *Parser) parseFunctionDeclaration() *ast.FunctionDeclaration {
-	fnDecl := &ast.FunctionDeclaration{Token: p.curToken}
+	fnDecl := &ast.FunctionDeclaration{Token: p.curToken, Doc: p.curDoc}
 
 	if !p.expectPeek(token.IDENT) {
 		return nil
 	}
-	fnDecl.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Text}
+	fnDecl.Name = p.newIdentifier()
 
 	if !p.expectPeek(token.LPAREN) {
 		return nil
@@ -257,9 +652,9 @@ func (p *Parser) parseFunctionDeclaration() *ast.FunctionDeclaration {
 	}
 
 	// Optional return type
-	if p.peekToken.IsType() {
-		p.nextToken() // Advance to the type token
-		fnDecl.ReturnType = &ast.TypeLiteral{Token: p.curToken, Value: p.curToken.Text}
+	if p.peekIsType() {
+		p.nextToken() // Advance to the start of the type
+		fnDecl.ReturnType = p.parseType()
 	}
 
 	if !p.expectPeek(token.LBRACE) {
@@ -268,13 +663,14 @@ func (p *Parser) parseFunctionDeclaration() *ast.FunctionDeclaration {
 
 	fnDecl.Body = p.parseBlockStatement()
 
+	fnDecl.Loc = p.spanFrom(fnDecl.Token)
 	return fnDecl
 }
 
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefix := p.prefixParseFns[p.curToken.Type]
 	if prefix == nil {
-		p.noPrefixParseFnError(p.curToken.Type)
+		p.noPrefixParseFnError(p.curToken)
 		return nil
 	}
 	leftExp := prefix()
@@ -292,16 +688,220 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 }
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Text}
+	return p.newIdentifier()
+}
+
+// newIdentifier builds an identifier from the current token.
+func (p *Parser) newIdentifier() *ast.Identifier {
+	return &ast.Identifier{Token: p.curToken, Loc: p.curToken.Span, Value: p.curToken.Text}
+}
+
+// peekIsType reports whether the peek token starts a type expression.
+// A name is taken to be the name of a declared type.
+func (p *Parser) peekIsType() bool {
+	return p.peekToken.IsType() || p.peekTokenIs(token.FN) ||
+		p.peekTokenIs(token.STRUCT) || p.peekTokenIs(token.LBRACKET) ||
+		p.peekTokenIs(token.MAP) || p.peekTokenIs(token.IDENT)
+}
+
+// parseType parses the type expression starting at the current token.
+func (p *Parser) parseType() ast.TypeExpression {
+	if p.curTokenIs(token.FN) {
+		if ft := p.parseFunctionType(); ft != nil {
+			return ft
+		}
+		return nil
+	}
+
+	if p.curTokenIs(token.STRUCT) {
+		if st := p.parseStructType(); st != nil {
+			return st
+		}
+		return nil
+	}
+
+	if p.curTokenIs(token.LBRACKET) {
+		if at := p.parseArrayType(); at != nil {
+			return at
+		}
+		return nil
+	}
+
+	if p.curTokenIs(token.MAP) {
+		if mt := p.parseMapType(); mt != nil {
+			return mt
+		}
+		return nil
+	}
+
+	if !p.curToken.IsType() && !p.curTokenIs(token.IDENT) {
+		p.errorf(p.curToken.Span, "expected type, got %s", p.curToken.Text)
+		return nil
+	}
+	return p.newTypeLiteral()
+}
+
+// parseFunctionType parses a function type such as fn(int, string) bool.
+// The current token is FN.
+func (p *Parser) parseFunctionType() *ast.FunctionType {
+	ft := &ast.FunctionType{Token: p.curToken, Params: []ast.TypeExpression{}}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken() // Advance to RPAREN
+	} else {
+		for {
+			p.nextToken() // Advance past LPAREN or COMMA
+			param := p.parseType()
+			if param == nil {
+				return nil
+			}
+			ft.Params = append(ft.Params, param)
+
+			if !p.peekTokenIs(token.COMMA) {
+				break
+			}
+			p.nextToken() // Advance to COMMA
+		}
+
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+	}
+
+	if p.peekIsType() {
+		p.nextToken() // Advance to the start of the result type
+		if ft.Result = p.parseType(); ft.Result == nil {
+			return nil
+		}
+	}
+
+	ft.Loc = p.spanFrom(ft.Token)
+	return ft
+}
+
+// parseArrayType parses an array type such as [3]int or a slice type
+// such as []int. The current token is LBRACKET.
+func (p *Parser) parseArrayType() *ast.ArrayType {
+	at := &ast.ArrayType{Token: p.curToken}
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken() // Advance past LBRACKET
+		if at.Len = p.parseExpression(LOWEST); at.Len == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	p.nextToken() // Advance to the start of the element type
+	if at.Elem = p.parseType(); at.Elem == nil {
+		return nil
+	}
+
+	at.Loc = p.spanFrom(at.Token)
+	return at
+}
+
+// parseMapType parses a map type such as map[string]int. The current
+// token is MAP.
+func (p *Parser) parseMapType() *ast.MapType {
+	mt := &ast.MapType{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACKET) {
+		return nil
+	}
+
+	p.nextToken() // Advance to the start of the key type
+	if mt.Key = p.parseType(); mt.Key == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	p.nextToken() // Advance to the start of the value type
+	if mt.Value = p.parseType(); mt.Value == nil {
+		return nil
+	}
+
+	mt.Loc = p.spanFrom(mt.Token)
+	return mt
+}
+
+// parseStructType parses a struct type such as struct { x int; y int }.
+// The current token is STRUCT.
+func (p *Parser) parseStructType() *ast.StructType {
+	st := &ast.StructType{Token: p.curToken, Fields: []*ast.StructField{}}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		field := &ast.StructField{Token: p.curToken, Name: p.newIdentifier()}
+		p.nextToken() // Advance to the start of the type
+		if field.Type = p.parseType(); field.Type == nil {
+			return nil
+		}
+		field.Loc = p.spanFrom(field.Token)
+		st.Fields = append(st.Fields, field)
+
+		// The semicolon after the last field is optional.
+		if !p.peekTokenIs(token.SEMICOLON) {
+			break
+		}
+		p.nextToken() // Advance to SEMICOLON
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	st.Loc = p.spanFrom(st.Token)
+	return st
+}
+
+// newTypeLiteral builds a type literal from the current token.
+func (p *Parser) newTypeLiteral() *ast.TypeLiteral {
+	return &ast.TypeLiteral{Token: p.curToken, Loc: p.curToken.Span, Value: p.curToken.Text}
 }
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
-	lit := &ast.IntegerLiteral{Token: p.curToken}
+	lit := &ast.IntegerLiteral{Token: p.curToken, Loc: p.curToken.Span}
+
+	// The range of the value depends on the type it is used as, which the
+	// analyzer checks.
+	value, ok := new(big.Int).SetString(p.curToken.Text, 0)
+	if !ok {
+		p.errorf(p.curToken.Span, "could not parse %q as integer", p.curToken.Text)
+		return nil
+	}
 
-	value, err := strconv.ParseInt(p.curToken.Text, 0, 64)
+	lit.Value = value
+	return lit
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken, Loc: p.curToken.Span}
+
+	value, err := strconv.ParseFloat(p.curToken.Text, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Text)
-		p.errors = append(p.errors, msg)
+		if errors.Is(err, strconv.ErrRange) {
+			p.errorf(p.curToken.Span, "float literal %s out of range", p.curToken.Text)
+		} else {
+			p.errorf(p.curToken.Span, "could not parse %q as float", p.curToken.Text)
+		}
 		return nil
 	}
 
@@ -310,7 +910,21 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 }
 
 func (p *Parser) parseStringLiteral() ast.Expression {
-	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Text}
+	value, err := lexer.Unquote(p.curToken.Text)
+	if err != nil {
+		p.errorf(p.curToken.Span, "invalid string literal %s: %v", p.curToken.Text, err)
+		return nil
+	}
+
+	return &ast.StringLiteral{Token: p.curToken, Loc: p.curToken.Span, Value: value}
+}
+
+func (p *Parser) parseBooleanLiteral() ast.Expression {
+	return &ast.BooleanLiteral{
+		Token: p.curToken,
+		Loc:   p.curToken.Span,
+		Value: p.curTokenIs(token.TRUE),
+	}
 }
 
 func (p *Parser) parsePrefixExpression() ast.Expression {
@@ -323,6 +937,7 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 
 	expression.Right = p.parseExpression(PREFIX)
 
+	expression.Loc = p.spanFrom(expression.Token)
 	return expression
 }
 
@@ -338,10 +953,12 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 
 	expression.Right = p.parseExpression(precedence)
 
+	expression.Loc = p.spanFromNode(left)
 	return expression
 }
 
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	defer p.structLiterals(true)()
 	p.nextToken() // Advance past LPAREN
 
 	exp := p.parseExpression(LOWEST)
@@ -364,16 +981,17 @@ func (p *Parser) parseFunctionParameters() []*ast.FunctionParameter {
 
 	param := &ast.FunctionParameter{Token: p.curToken}
 	if !p.curTokenIs(token.IDENT) {
-		p.errors = append(p.errors, "expected identifier for function parameter")
+		p.errorf(p.curToken.Span, "expected identifier for function parameter")
 		return nil
 	}
-	param.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Text}
+	param.Name = p.newIdentifier()
 
 	// Optional type annotation
-	if p.peekToken.IsType() {
-		p.nextToken() // Advance to the type token
-		param.Type = &ast.TypeLiteral{Token: p.curToken, Value: p.curToken.Text}
+	if p.peekIsType() {
+		p.nextToken() // Advance to the start of the type
+		param.Type = p.parseType()
 	}
+	param.Loc = p.spanFrom(param.Token)
 	parameters = append(parameters, param)
 
 	for p.peekTokenIs(token.COMMA) {
@@ -382,16 +1000,17 @@ func (p *Parser) parseFunctionParameters() []*ast.FunctionParameter {
 
 		param := &ast.FunctionParameter{Token: p.curToken}
 		if !p.curTokenIs(token.IDENT) {
-			p.errors = append(p.errors, "expected identifier for function parameter")
+			p.errorf(p.curToken.Span, "expected identifier for function parameter")
 			return nil
 		}
-		param.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Text}
+		param.Name = p.newIdentifier()
 
 		// Optional type annotation
-		if p.peekToken.IsType() {
-			p.nextToken() // Advance to the type token
-			param.Type = &ast.TypeLiteral{Token: p.curToken, Value: p.curToken.Text}
+		if p.peekIsType() {
+			p.nextToken() // Advance to the start of the type
+			param.Type = p.parseType()
 		}
+		param.Loc = p.spanFrom(param.Token)
 		parameters = append(parameters, param)
 	}
 
@@ -412,9 +1031,9 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	}
 
 	// Optional return type
-	if p.peekToken.IsType() {
-		p.nextToken() // Advance to the type token
-		lit.ReturnType = &ast.TypeLiteral{Token: p.curToken, Value: p.curToken.Text}
+	if p.peekIsType() {
+		p.nextToken() // Advance to the start of the type
+		lit.ReturnType = p.parseType()
 	}
 
 	if !p.expectPeek(token.LBRACE) {
@@ -423,17 +1042,40 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 
 	lit.Body = p.parseBlockStatement()
 
+	lit.Loc = p.spanFrom(lit.Token)
 	return lit
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseCallArguments()
+	exp.Loc = p.spanFromNode(function)
+	return exp
+}
+
+// parseConversionExpression parses an explicit conversion such as
+// int8(x). The current token is the type name.
+func (p *Parser) parseConversionExpression() ast.Expression {
+	exp := &ast.ConversionExpression{Token: p.curToken, Type: p.newTypeLiteral()}
+	defer p.structLiterals(true)()
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken() // Advance past LPAREN
+
+	exp.Value = p.parseExpression(LOWEST)
+	if exp.Value == nil || !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	exp.Loc = p.spanFrom(exp.Token)
 	return exp
 }
 
 func (p *Parser) parseCallArguments() []ast.Expression {
 	args := []ast.Expression{}
+	defer p.structLiterals(true)()
 
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken() // Advance past RPAREN
@@ -457,6 +1099,169 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 	return args
 }
 
+// parseArrayLiteral parses a list of values such as [1, 2, 3]. The
+// current token is LBRACKET.
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	lit := &ast.ArrayLiteral{Token: p.curToken, Elements: []ast.Expression{}}
+	defer p.structLiterals(true)()
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken() // Advance to the element
+		element := p.parseExpression(LOWEST)
+		if element == nil {
+			return nil
+		}
+		lit.Elements = append(lit.Elements, element)
+
+		// The comma after the last element is optional.
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken() // Advance to COMMA
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	lit.Loc = p.spanFrom(lit.Token)
+	return lit
+}
+
+// parseMapLiteral parses a map literal such as {"one": 1, "two": 2}. The
+// current token is LBRACE.
+func (p *Parser) parseMapLiteral() ast.Expression {
+	lit := &ast.MapLiteral{Token: p.curToken, Pairs: []*ast.KeyValue{}}
+	defer p.structLiterals(true)()
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken() // Advance to the key
+		key := p.parseExpression(LOWEST)
+		if key == nil || !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		pair := &ast.KeyValue{Token: p.curToken, Key: key}
+		p.nextToken() // Advance past COLON
+
+		if pair.Value = p.parseExpression(LOWEST); pair.Value == nil {
+			return nil
+		}
+		pair.Loc = p.spanFromNode(key)
+		lit.Pairs = append(lit.Pairs, pair)
+
+		// The comma after the last pair is optional.
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken() // Advance to COMMA
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	lit.Loc = p.spanFrom(lit.Token)
+	return lit
+}
+
+// parseIndexExpression parses an index expression such as a[i] or a slice
+// expression such as a[i:j], where either bound may be omitted. The
+// current token is the LBRACKET.
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	tok := p.curToken
+	defer p.structLiterals(true)()
+
+	var low ast.Expression
+	if !p.peekTokenIs(token.COLON) {
+		p.nextToken() // Advance past LBRACKET
+		if low = p.parseExpression(LOWEST); low == nil {
+			return nil
+		}
+	}
+
+	if !p.peekTokenIs(token.COLON) {
+		if low == nil || !p.expectPeek(token.RBRACKET) {
+			return nil
+		}
+		return &ast.IndexExpression{Token: tok, Loc: p.spanFromNode(left), Left: left, Index: low}
+	}
+
+	exp := &ast.SliceExpression{Token: tok, Left: left, Low: low}
+	p.nextToken() // Advance to COLON
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken() // Advance past COLON
+		if exp.High = p.parseExpression(LOWEST); exp.High == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	exp.Loc = p.spanFromNode(left)
+	return exp
+}
+
+// parseStructLiteral parses a struct literal such as Point{x: 1, y: 2}.
+// The current token is the LBRACE after the name of the type.
+func (p *Parser) parseStructLiteral(typ ast.Expression) ast.Expression {
+	lit := &ast.StructLiteral{Token: p.curToken, Type: typ, Fields: []*ast.FieldValue{}}
+	defer p.structLiterals(true)()
+
+	if _, ok := typ.(*ast.Identifier); !ok {
+		p.errorf(p.curToken.Span, "expected type name before '{', got %s", typ)
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		field := &ast.FieldValue{Token: p.curToken, Name: p.newIdentifier()}
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.nextToken() // Advance past COLON
+
+		if field.Value = p.parseExpression(LOWEST); field.Value == nil {
+			return nil
+		}
+		field.Loc = p.spanFrom(field.Token)
+		lit.Fields = append(lit.Fields, field)
+
+		// The comma after the last field is optional.
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken() // Advance to COMMA
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	lit.Loc = p.spanFromNode(typ)
+	return lit
+}
+
+// parseSelectorExpression parses a field access such as p.x. The current
+// token is the DOT.
+func (p *Parser) parseSelectorExpression(left ast.Expression) ast.Expression {
+	exp := &ast.SelectorExpression{Token: p.curToken, Left: left}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Field = p.newIdentifier()
+
+	exp.Loc = p.spanFromNode(left)
+	return exp
+}
+
 func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
 	exp := &ast.AssignmentExpression{Token: p.curToken, Left: left}
 
@@ -465,6 +1270,39 @@ func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
 
 	exp.Value = p.parseExpression(precedence)
 
+	exp.Loc = p.spanFromNode(left)
+	return exp
+}
+
+// parseMultiAssignment parses an assignment to several targets such as
+// v, ok = m[k]. first is the first target; the peek token is the COMMA
+// after it.
+func (p *Parser) parseMultiAssignment(first ast.Expression) ast.Expression {
+	exp := &ast.MultiAssignmentExpression{Left: []ast.Expression{first}}
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken() // Advance to COMMA
+		p.nextToken() // Advance to the next target
+
+		// Stop before '=', which belongs to the whole list.
+		target := p.parseExpression(ASSIGN)
+		if target == nil {
+			return nil
+		}
+		exp.Left = append(exp.Left, target)
+	}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	exp.Token = p.curToken
+	p.nextToken() // Advance past ASSIGN
+
+	if exp.Value = p.parseExpression(LOWEST); exp.Value == nil {
+		return nil
+	}
+
+	exp.Loc = p.spanFromNode(first)
 	return exp
 }
 
@@ -487,12 +1325,23 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 }
 
 func (p *Parser) peekPrecedence() int {
+	if p.noStructLiteral && p.peekTokenIs(token.LBRACE) {
+		return LOWEST
+	}
 	if p, ok := precedences[p.peekToken.Type]; ok {
 		return p
 	}
 	return LOWEST
 }
 
+// structLiterals sets whether '{' may continue an expression as a struct
+// literal and returns a function that restores the previous setting.
+func (p *Parser) structLiterals(allowed bool) (restore func()) {
+	prev := p.noStructLiteral
+	p.noStructLiteral = !allowed
+	return func() { p.noStructLiteral = prev }
+}
+
 func (p *Parser) curPrecedence() int {
 	if p, ok := precedences[p.curToken.Type]; ok {
 		return p
@@ -508,7 +1357,6 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 	p.infixParseFns[tokenType] = fn
 }
 
-func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t.String())
-	p.errors = append(p.errors, msg)
+func (p *Parser) noPrefixParseFnError(t token.Token) {
+	p.errorf(t.Span, "no prefix parse function for %s found", t.Type.String())
 }
@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"fmt"
+
+	"ixion/internal/token"
+)
+
+type ParserError struct {
+	Span    token.Span
+	Message string
+}
+
+func newError(span token.Span, msg string) error {
+	return &ParserError{
+		Span:    span,
+		Message: msg,
+	}
+}
+
+// Pos returns the position the error starts at.
+func (p *ParserError) Pos() token.Pos {
+	return p.Span.Start
+}
+
+func (p *ParserError) Error() string {
+	return fmt.Sprintf("parser error at %s: %s", p.Span.Start, p.Message)
+}
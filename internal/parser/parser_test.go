@@ -0,0 +1,261 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"ixion/internal/ast"
+	"ixion/internal/lexer"
+	"ixion/internal/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseProgram(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	toks, err := lexer.Tokenize(input)
+	require.NoError(t, err)
+
+	p := parser.New(toks)
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	return program
+}
+
+func parse(t *testing.T, input string) string {
+	t.Helper()
+
+	return parseProgram(t, input).String()
+}
+
+func TestParser_OperatorPrecedence(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"a + b * c;", "(a + (b * c))"},
+		{"a + b < c * d;", "((a + b) < (c * d))"},
+		{"a < b == c > d;", "((a < b) == (c > d))"},
+		{"a <= b != c >= d;", "((a <= b) != (c >= d))"},
+		{"x = a == b;", "x = (a == b)"},
+		{"!a == b;", "((!a) == b)"},
+		{"a || b && c;", "(a || (b && c))"},
+		{"a && b || c && d;", "((a && b) || (c && d))"},
+		{"a < b && c != d;", "((a < b) && (c != d))"},
+		{"!true || false;", "((!true) || false)"},
+		{"int8(a) + b;", "(int8(a) + b)"},
+		{"-float(a * b);", "(-float((a * b)))"},
+		{"p.x * q.y.z;", "(p.x * q.y.z)"},
+		{"f(a).b + c;", "(f(a).b + c)"},
+		{"-a[i + 1] * b;", "((-a[(i + 1)]) * b)"},
+		{"a[1:][:n - 1][0].x;", "a[1:][:(n - 1)][0].x"},
+		{"f(a)[0](b);", "f(a)[0](b)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, parse(t, tt.input))
+		})
+	}
+}
+
+func TestParser_Statements(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "if",
+			input: "if a < b { print(a); }",
+			want:  "if (a < b) {PRINT(a);}",
+		},
+		{
+			name:  "if else if else",
+			input: "if a { print(1); } else if b { print(2); } else { print(3); }",
+			want:  "if a {PRINT(1);} else if b {PRINT(2);} else {PRINT(3);}",
+		},
+		{
+			name:  "const",
+			input: "const limit int = 10 * 2;",
+			want:  "CONST limit int = (10 * 2);",
+		},
+		{
+			name:  "infinite for",
+			input: "for { print(1); }",
+			want:  "for {PRINT(1);}",
+		},
+		{
+			name:  "while-style for",
+			input: "for i < 10 { i = i + 1; }",
+			want:  "for (i < 10) {i = (i + 1)}",
+		},
+		{
+			name:  "c-style for",
+			input: "for var i = 0; i < 10; i = i + 1 { print(i); }",
+			want:  "for VAR i = 0; (i < 10); i = (i + 1) {PRINT(i);}",
+		},
+		{
+			name:  "c-style for with empty clauses",
+			input: "for ; ; { print(1); }",
+			want:  "for {PRINT(1);}",
+		},
+		{
+			name:  "var without initializer",
+			input: "var x int; x = 1;",
+			want:  "VAR x int;x = 1",
+		},
+		{
+			name:  "conversion",
+			input: "var x uint8 = uint8(y + 1);",
+			want:  "VAR x uint8 = uint8((y + 1));",
+		},
+		{
+			name:  "function type annotations",
+			input: "fn apply(f fn(int, string) bool, x int) fn() int { return g; }",
+			want:  "FN apply(f fn(int, string) bool, x int) fn() int RETURN g;",
+		},
+		{
+			name:  "variable of function type",
+			input: "var cb fn(fn(int)) = handler;",
+			want:  "VAR cb fn(fn(int)) = handler;",
+		},
+		{
+			name:  "struct type declaration",
+			input: "type Point struct { x int; y fn(int) Point; }",
+			want:  "TYPE Point struct{x int; y fn(int) Point};",
+		},
+		{
+			name:  "struct literal and field assignment",
+			input: "var p Point = Point{x: 1, y: a + b,}; p.x = p.y;",
+			want:  "VAR p Point = Point{x: 1, y: (a + b)};p.x = p.y",
+		},
+		{
+			name:  "struct literal in condition",
+			input: "if p == (Point{x: 1}) { print(f(Point{})); }",
+			want:  "if (p == Point{x: 1}) {PRINT(f(Point{}));}",
+		},
+		{
+			name:  "field in loop condition",
+			input: "for p.x < n { print(p.x); }",
+			want:  "for (p.x < n) {PRINT(p.x);}",
+		},
+		{
+			name:  "array and slice types",
+			input: "var grid [N * 2][]string = [[\"a\"], [],];",
+			want:  "VAR grid [(N * 2)][]string = [[\"a\"], []];",
+		},
+		{
+			name:  "index assignment and slicing",
+			input: "fn f(xs []int) { xs[0] = len(xs[1:2]) + len(xs[:]); }",
+			want:  "FN f(xs []int) xs[0] = (len(xs[1:2]) + len(xs[:]))",
+		},
+		{
+			name:  "index in loop condition",
+			input: "for xs[i] > 0 { print([i]); }",
+			want:  "for (xs[i] > 0) {PRINT([i]);}",
+		},
+		{
+			name:  "map type and literal",
+			input: "var m map[string][]int = {\"a\": [1], k + \"b\": [],};",
+			want:  "VAR m map[string][]int = {\"a\": [1], (k + \"b\"): []};",
+		},
+		{
+			name:  "map lookup and comma-ok assignment",
+			input: "m[k] = len(m); v, ok = m[k];",
+			want:  "m[k] = len(m)v, ok = m[k]",
+		},
+		{
+			name:  "comma-ok assignment in for post",
+			input: "for var i = 0; ok; v, ok = m[i] { i = i + 1; }",
+			want:  "for VAR i = 0; ok; v, ok = m[i] {i = (i + 1)}",
+		},
+		{
+			name:  "labeled loop with break and continue",
+			input: "outer: for { for { continue outer; } break; }",
+			want:  "outer: for {for {CONTINUE outer;}BREAK;}",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parse(t, tt.input))
+		})
+	}
+}
+
+func TestParser_DocComments(t *testing.T) {
+	input := `// Version of the program.
+var version = 1; // not a doc comment
+/* Limit is
+ * the maximum. */
+const limit = 10;
+
+// Detached.
+
+fn run() { print(limit); }
+// Adds one.
+// Really.
+fn inc(x int) int { return x + 1; }`
+
+	program := parseProgram(t, input)
+	require.Len(t, program.Statements, 4)
+
+	assert.Equal(t, "Version of the program.", program.Statements[0].(*ast.VarStatement).Doc)
+	assert.Equal(t, "Limit is\nthe maximum.", program.Statements[1].(*ast.ConstStatement).Doc)
+	assert.Equal(t, "", program.Statements[2].(*ast.FunctionDeclaration).Doc)
+	assert.Equal(t, "Adds one.\nReally.", program.Statements[3].(*ast.FunctionDeclaration).Doc)
+}
+
+func TestParser_CompositeJSON(t *testing.T) {
+	program := parseProgram(t, "type P struct { x int } var p = P{x: 1}; print(p.x);\nvar a [2][]int = [[1], [2]]; print(a[0][1:]);\nvar m map[int]bool = {1: true}; v, ok = m[1];")
+
+	out, err := json.Marshal(program)
+	require.NoError(t, err)
+
+	for _, node := range []string{"TypeDeclaration", "StructType", "StructField", "StructLiteral", "FieldValue", "SelectorExpression",
+		"ArrayType", "ArrayLiteral", "IndexExpression", "SliceExpression",
+		"MapType", "MapLiteral", "KeyValue", "MultiAssignmentExpression"} {
+		assert.Contains(t, string(out), `"type":"`+node+`"`)
+	}
+}
+
+func TestParser_NumericLiterals(t *testing.T) {
+	program := parseProgram(t, "0xFF; 0o17; 0b1010; 1_000; 2.5e-3;")
+	require.Len(t, program.Statements, 5)
+
+	var got []any
+	for _, stmt := range program.Statements {
+		switch lit := stmt.(*ast.ExpressionStatement).Expression.(type) {
+		case *ast.IntegerLiteral:
+			got = append(got, lit.Value)
+		case *ast.FloatLiteral:
+			got = append(got, lit.Value)
+		}
+	}
+
+	assert.Equal(t, []any{big.NewInt(255), big.NewInt(15), big.NewInt(10), big.NewInt(1000), 2.5e-3}, got)
+}
+
+func TestParser_OutOfRangeLiterals(t *testing.T) {
+	// Integer literals of any size parse; the analyzer checks them against
+	// the type they are used as.
+	toks, err := lexer.Tokenize("var a = 0xFFFFFFFFFFFFFFFF;\nvar b = 1e400;\nvar c = 123456789012345678901234567890;")
+	require.NoError(t, err)
+
+	p := parser.New(toks)
+	p.ParseProgram()
+
+	var msgs []string
+	for _, err := range p.Errors() {
+		msgs = append(msgs, err.Error())
+	}
+
+	assert.Equal(t, []string{
+		"parser error at 2:9: float literal 1e400 out of range",
+	}, msgs)
+}
@@ -0,0 +1,243 @@
+package semantic
+
+import (
+	"math/big"
+
+	"ixion/internal/ast"
+	"ixion/internal/types"
+)
+
+// arrayType resolves an array or slice type annotation.
+func (a *Analyzer) arrayType(at *ast.ArrayType) types.Type {
+	if at.Len == nil {
+		// A slice refers to its elements indirectly, so its element type
+		// may be the type being declared.
+		a.indirect++
+		defer func() { a.indirect-- }()
+		return types.NewSlice(a.typeOf(at.Elem))
+	}
+
+	return types.NewArray(a.typeOf(at.Elem), a.arrayLength(at.Len))
+}
+
+// arrayLength evaluates the length of an array type, which must be a
+// non-negative integer constant.
+func (a *Analyzer) arrayLength(expr ast.Expression) int64 {
+	errCount := len(a.Errors)
+	t := a.visitValue(expr)
+
+	value, ok := a.evalConst(expr)
+	if !ok {
+		if len(a.Errors) == errCount {
+			a.errf(expr, "array length %s is not a constant", expr)
+		}
+		return 0
+	}
+
+	v, isInt := value.(*big.Int)
+	if !isInt || !types.IsInteger(t) {
+		a.errf(expr, "array length %s (type %s) must be integer", expr, t)
+		return 0
+	}
+	if v.Sign() < 0 || !v.IsInt64() {
+		a.errf(expr, "invalid array length %s", expr)
+		return 0
+	}
+
+	return v.Int64()
+}
+
+// visitArrayLiteral checks the elements of an array literal, which must
+// have a common type, and returns a slice of that type. The elements of
+// a literal without elements take their type from where it is used.
+func (a *Analyzer) visitArrayLiteral(al *ast.ArrayLiteral) types.Type {
+	elem, ok := invalidType, true
+	for i, element := range al.Elements {
+		elem, ok = a.commonType(elem, element, a.visitValue(element), i == 0, "array literal")
+		if !ok {
+			return types.NewSlice(invalidType)
+		}
+	}
+
+	return types.NewSlice(elem)
+}
+
+// commonType merges the type t of an element of a literal into common,
+// the type of the elements before it, and returns the type of them all.
+// It reports false if the types do not match.
+func (a *Analyzer) commonType(common types.Type, element ast.Expression, t types.Type, first bool, literal string) (types.Type, bool) {
+	switch {
+	case first || types.IsInvalid(common):
+		return t, true
+	case types.IsInvalid(t) || isEmptyLiteral(element):
+		// Any type fits.
+		return common, true
+	}
+
+	merged, ok := operandType(common, t)
+	if !ok {
+		a.errf(element, "mismatched types %s and %s in %s", common, t, literal)
+		return invalidType, false
+	}
+	return merged, true
+}
+
+// isEmptyLiteral reports whether expr is an array or map literal without
+// elements, whose type is only known from where it is used.
+func isEmptyLiteral(expr ast.Expression) bool {
+	switch lit := expr.(type) {
+	case *ast.ArrayLiteral:
+		return len(lit.Elements) == 0
+	case *ast.MapLiteral:
+		return len(lit.Pairs) == 0
+	default:
+		return false
+	}
+}
+
+// checkArrayLiteral checks an array literal used as a value of type
+// target, element by element. It reports false if target is neither an
+// array nor a slice type, leaving the literal to the ordinary rules.
+func (a *Analyzer) checkArrayLiteral(al *ast.ArrayLiteral, litType, target types.Type, context string) bool {
+	lit, ok := litType.(*types.Slice)
+	if !ok {
+		return false
+	}
+
+	var elem types.Type
+	switch t := target.Underlying().(type) {
+	case *types.Array:
+		if int64(len(al.Elements)) != t.Len {
+			a.errf(al, "cannot use %s (array literal of length %d) as %s value in %s",
+				al, len(al.Elements), target, context)
+			return true
+		}
+		elem = t.Elem
+	case *types.Slice:
+		elem = t.Elem
+	default:
+		return false
+	}
+
+	for _, element := range al.Elements {
+		a.checkAssignable(element, lit.Elem, elem, context)
+	}
+	return true
+}
+
+// elementType returns the type of the elements of an array or slice type,
+// and the length of an array type or -1.
+func elementType(t types.Type) (elem types.Type, length int64, ok bool) {
+	switch u := t.Underlying().(type) {
+	case *types.Array:
+		return u.Elem, u.Len, true
+	case *types.Slice:
+		return u.Elem, -1, true
+	default:
+		return nil, 0, false
+	}
+}
+
+// visitIndexExpression checks an access to an element of an array or
+// slice, or to the value of a key in a map, and returns the type of the
+// element or value.
+func (a *Analyzer) visitIndexExpression(ie *ast.IndexExpression) types.Type {
+	t, _ := a.visitIndex(ie)
+	return t
+}
+
+// visitIndex is visitIndexExpression, also reporting whether ie looks up
+// a key in a map.
+func (a *Analyzer) visitIndex(ie *ast.IndexExpression) (types.Type, bool) {
+	t := a.visitValue(ie.Left)
+
+	if m, ok := t.Underlying().(*types.Map); ok {
+		a.checkAssignable(ie.Index, a.visitValue(ie.Index), m.Key, "map index")
+		return m.Elem, true
+	}
+
+	elem, length, ok := elementType(t)
+	if !ok {
+		a.visitValue(ie.Index)
+		if !types.IsInvalid(t) {
+			a.errf(ie, "cannot index %s (type %s)", ie.Left, t)
+		}
+		return invalidType, false
+	}
+
+	a.checkIndex(ie.Index, length, false)
+	return elem, false
+}
+
+// visitSliceExpression checks a slice expression and returns the slice
+// type it yields.
+func (a *Analyzer) visitSliceExpression(se *ast.SliceExpression) types.Type {
+	t := a.visitValue(se.Left)
+
+	elem, length, ok := elementType(t)
+	if !ok {
+		for _, bound := range []ast.Expression{se.Low, se.High} {
+			if bound != nil {
+				a.visitValue(bound)
+			}
+		}
+		if !types.IsInvalid(t) {
+			a.errf(se, "cannot slice %s (type %s)", se.Left, t)
+		}
+		return invalidType
+	}
+
+	var low, high *big.Int
+	if se.Low != nil {
+		low = a.checkIndex(se.Low, length, true)
+	}
+	if se.High != nil {
+		high = a.checkIndex(se.High, length, true)
+	}
+	if low != nil && high != nil && low.Cmp(high) > 0 {
+		a.errf(se, "invalid slice indices: %s > %s", low, high)
+	}
+
+	// Slicing an array literal gives its elements their default type.
+	return types.Default(types.NewSlice(elem))
+}
+
+// checkIndex checks an index or a slice bound. A constant index must not
+// be negative and, for an array of known length, must be less than the
+// length, or at most the length for a slice bound. The value of a valid
+// constant index is returned.
+func (a *Analyzer) checkIndex(index ast.Expression, length int64, bound bool) *big.Int {
+	t := a.visitValue(index)
+	if types.IsInvalid(t) {
+		return nil
+	}
+	if !types.IsInteger(t) {
+		a.errf(index, "index %s (type %s) must be integer", index, t)
+		return nil
+	}
+
+	value, ok := a.evalConst(index)
+	if !ok {
+		return nil
+	}
+
+	v, isInt := value.(*big.Int)
+	if !isInt {
+		return nil
+	}
+
+	limit := big.NewInt(length)
+	if bound {
+		limit.Add(limit, big.NewInt(1))
+	}
+
+	switch {
+	case v.Sign() < 0:
+		a.errf(index, "index %s must not be negative", v)
+		return nil
+	case length >= 0 && v.Cmp(limit) >= 0:
+		a.errf(index, "index %s out of bounds for %d-element array", v, length)
+		return nil
+	}
+	return v
+}
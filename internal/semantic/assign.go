@@ -0,0 +1,69 @@
+package semantic
+
+import (
+	"maps"
+
+	"ixion/internal/ast"
+)
+
+// assignState is the set of variables that were declared without an
+// initializer and are not assigned on every path to the current statement.
+type assignState map[*Symbol]bool
+
+func (s assignState) clone() assignState {
+	return maps.Clone(s)
+}
+
+// join returns the state after two paths meet: a variable is assigned
+// only if it is assigned on both.
+func (s assignState) join(other assignState) assignState {
+	joined := s.clone()
+	maps.Copy(joined, other)
+	return joined
+}
+
+// checkRead reports a read of a variable that has no value yet, either
+// because the read is part of its own initializer or because it is not
+// assigned on every path to the read.
+func (a *Analyzer) checkRead(node ast.Node, symbol *Symbol) {
+	if symbol.initializing {
+		a.errf(node, "variable '%s' used in its own initializer", symbol.Name)
+		return
+	}
+
+	if a.unassigned[symbol] {
+		a.errf(node, "variable '%s' is used before being assigned", symbol.Name)
+		// Report each variable once.
+		delete(a.unassigned, symbol)
+	}
+}
+
+// branchState visits a branch of an if statement starting from state and
+// returns the state at its end, and whether control reaches that end.
+func (a *Analyzer) branchState(branch ast.Statement, state assignState) (assignState, bool) {
+	a.unassigned = state.clone()
+	if branch == nil {
+		return a.unassigned, true
+	}
+
+	a.visitStmt(branch)
+	return a.unassigned, fallsThrough(branch)
+}
+
+// fallsThrough reports whether control can continue with the statement
+// after stmt.
+func fallsThrough(stmt ast.Statement) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStatement, *ast.BreakStatement, *ast.ContinueStatement:
+		return false
+	case *ast.BlockStatement:
+		if len(s.Statements) == 0 {
+			return true
+		}
+		return fallsThrough(s.Statements[len(s.Statements)-1])
+	case *ast.IfStatement:
+		return s.Alternative == nil || fallsThrough(s.Consequence) || fallsThrough(s.Alternative)
+	default:
+		return !isTerminating(stmt, "")
+	}
+}
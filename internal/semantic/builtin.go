@@ -0,0 +1,47 @@
+package semantic
+
+import (
+	"ixion/internal/ast"
+	"ixion/internal/types"
+)
+
+// builtins are the predeclared functions. They live in the universe
+// scope, which encloses the global scope, so a program may redeclare
+// them.
+var builtins = []string{"len"}
+
+func newUniverse() *Scope {
+	universe := &Scope{Symbols: make(map[string]*Symbol)}
+	for _, name := range builtins {
+		universe.Symbols[name] = &Symbol{Name: name, Type: invalidType, Kind: BuiltinSymbol, Scope: universe}
+	}
+	return universe
+}
+
+// visitBuiltinCall checks a call to a built-in function and returns the
+// type of its result.
+func (a *Analyzer) visitBuiltinCall(ce *ast.CallExpression, builtin *Symbol) types.Type {
+	argTypes := make([]types.Type, len(ce.Arguments))
+	for i, arg := range ce.Arguments {
+		argTypes[i] = a.visitValue(arg)
+	}
+
+	switch builtin.Name {
+	case "len":
+		if !a.checkArgCount(ce, builtin.Name, 1) {
+			return intType
+		}
+
+		t := argTypes[0]
+		switch t.Underlying().(type) {
+		case *types.Array, *types.Slice, *types.Map:
+		default:
+			if !types.IsString(t) && !types.IsInvalid(t) {
+				a.errf(ce.Arguments[0], "invalid argument %s (type %s) for built-in len", ce.Arguments[0], t)
+			}
+		}
+		return intType
+	default:
+		return invalidType
+	}
+}
@@ -0,0 +1,261 @@
+package semantic
+
+import (
+	"math"
+	"math/big"
+
+	"ixion/internal/ast"
+	"ixion/internal/types"
+)
+
+// evalConst evaluates a constant expression at compile time. Integer
+// constants are *big.Int so that overflow can be detected against the
+// declared type, floats are float64, strings are string and booleans are
+// bool.
+//
+// ok is false if expr is not a constant expression. Errors that make an
+// otherwise constant expression invalid, such as a division by zero, are
+// reported directly, and only the first time expr is evaluated.
+func (a *Analyzer) evalConst(expr ast.Expression) (value any, ok bool) {
+	if c, seen := a.consts[expr]; seen {
+		return c.value, c.ok
+	}
+
+	value, ok = a.evalConstExpr(expr)
+	a.consts[expr] = constant{value: value, ok: ok}
+	return value, ok
+}
+
+func (a *Analyzer) evalConstExpr(expr ast.Expression) (any, bool) {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return new(big.Int).Set(e.Value), true
+	case *ast.FloatLiteral:
+		return e.Value, true
+	case *ast.StringLiteral:
+		return e.Value, true
+	case *ast.BooleanLiteral:
+		return e.Value, true
+	case *ast.Identifier:
+		symbol := a.resolve(e.Value)
+		if symbol == nil || symbol.Kind != ConstantSymbol || symbol.Value == nil {
+			return nil, false
+		}
+		return symbol.Value, true
+	case *ast.PrefixExpression:
+		return a.evalConstPrefix(e)
+	case *ast.InfixExpression:
+		return a.evalConstInfix(e)
+	case *ast.ConversionExpression:
+		return a.evalConstConversion(e)
+	default:
+		return nil, false
+	}
+}
+
+func (a *Analyzer) evalConstPrefix(pe *ast.PrefixExpression) (any, bool) {
+	right, ok := a.evalConst(pe.Right)
+	if !ok {
+		return nil, false
+	}
+
+	switch r := right.(type) {
+	case *big.Int:
+		if pe.Operator == "-" {
+			return new(big.Int).Neg(r), true
+		}
+	case float64:
+		if pe.Operator == "-" {
+			return -r, true
+		}
+	case bool:
+		if pe.Operator == "!" {
+			return !r, true
+		}
+	}
+
+	return nil, false
+}
+
+func (a *Analyzer) evalConstInfix(ie *ast.InfixExpression) (any, bool) {
+	left, ok := a.evalConst(ie.Left)
+	if !ok {
+		return nil, false
+	}
+	right, ok := a.evalConst(ie.Right)
+	if !ok {
+		return nil, false
+	}
+
+	// An untyped int operand takes the kind of an untyped float operand.
+	switch l := left.(type) {
+	case *big.Int:
+		if _, ok := right.(float64); ok {
+			left = intToFloat(l)
+		}
+	case float64:
+		if r, ok := right.(*big.Int); ok {
+			right = intToFloat(r)
+		}
+	}
+
+	switch l := left.(type) {
+	case *big.Int:
+		r, ok := right.(*big.Int)
+		if !ok {
+			return nil, false
+		}
+		return a.evalConstInt(ie, l, r)
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, false
+		}
+		return a.evalConstFloat(ie, l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, false
+		}
+		switch ie.Operator {
+		case "+":
+			return l + r, true
+		case "==":
+			return l == r, true
+		case "!=":
+			return l != r, true
+		case "<":
+			return l < r, true
+		case ">":
+			return l > r, true
+		case "<=":
+			return l <= r, true
+		case ">=":
+			return l >= r, true
+		}
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			return nil, false
+		}
+		switch ie.Operator {
+		case "&&":
+			return l && r, true
+		case "||":
+			return l || r, true
+		case "==":
+			return l == r, true
+		case "!=":
+			return l != r, true
+		}
+	}
+
+	return nil, false
+}
+
+// intToFloat returns the float value nearest to x.
+func intToFloat(x *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(x).Float64()
+	return f
+}
+
+func (a *Analyzer) evalConstInt(ie *ast.InfixExpression, l, r *big.Int) (any, bool) {
+	switch ie.Operator {
+	case "+":
+		return new(big.Int).Add(l, r), true
+	case "-":
+		return new(big.Int).Sub(l, r), true
+	case "*":
+		return new(big.Int).Mul(l, r), true
+	case "/":
+		if r.Sign() == 0 {
+			a.err(ie, "division by zero in constant expression")
+			return nil, false
+		}
+		return new(big.Int).Quo(l, r), true
+	case "==":
+		return l.Cmp(r) == 0, true
+	case "!=":
+		return l.Cmp(r) != 0, true
+	case "<":
+		return l.Cmp(r) < 0, true
+	case ">":
+		return l.Cmp(r) > 0, true
+	case "<=":
+		return l.Cmp(r) <= 0, true
+	case ">=":
+		return l.Cmp(r) >= 0, true
+	default:
+		return nil, false
+	}
+}
+
+func (a *Analyzer) evalConstFloat(ie *ast.InfixExpression, l, r float64) (any, bool) {
+	switch ie.Operator {
+	case "+":
+		return l + r, true
+	case "-":
+		return l - r, true
+	case "*":
+		return l * r, true
+	case "/":
+		if r == 0 {
+			a.err(ie, "division by zero in constant expression")
+			return nil, false
+		}
+		return l / r, true
+	case "==":
+		return l == r, true
+	case "!=":
+		return l != r, true
+	case "<":
+		return l < r, true
+	case ">":
+		return l > r, true
+	case "<=":
+		return l <= r, true
+	case ">=":
+		return l >= r, true
+	default:
+		return nil, false
+	}
+}
+
+// evalConstConversion converts a constant to the target type of a
+// conversion. A float converts to an integer type only if it has no
+// fractional part; range checks are left to the caller.
+func (a *Analyzer) evalConstConversion(ce *ast.ConversionExpression) (any, bool) {
+	value, ok := a.evalConst(ce.Value)
+	if !ok {
+		return nil, false
+	}
+
+	target := a.typeOf(ce.Type)
+	switch v := value.(type) {
+	case *big.Int:
+		if types.IsInteger(target) {
+			return v, true
+		}
+		if types.IsFloat(target) {
+			return intToFloat(v), true
+		}
+	case float64:
+		if types.IsFloat(target) {
+			return v, true
+		}
+		if types.IsInteger(target) && v == math.Trunc(v) && !math.IsInf(v, 0) {
+			i, _ := big.NewFloat(v).Int(nil)
+			return i, true
+		}
+	case string:
+		if types.IsString(target) {
+			return v, true
+		}
+	case bool:
+		if types.IsBoolean(target) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
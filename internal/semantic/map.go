@@ -0,0 +1,122 @@
+package semantic
+
+import (
+	"fmt"
+
+	"ixion/internal/ast"
+	"ixion/internal/types"
+)
+
+// mapType resolves a map type annotation. The key type must be
+// comparable.
+func (a *Analyzer) mapType(mt *ast.MapType) types.Type {
+	key := a.typeOf(mt.Key)
+
+	// A map refers to its values indirectly, so their type may be the
+	// type being declared.
+	a.indirect++
+	elem := a.typeOf(mt.Value)
+	a.indirect--
+
+	// The key type may be a declared type that is not complete yet.
+	if key.Underlying() != nil && !types.Comparable(key) {
+		a.errf(mt.Key, "invalid map key type %s", key)
+	}
+
+	return types.NewMap(key, elem)
+}
+
+// visitMapLiteral checks the pairs of a map literal, whose keys and values
+// must each have a common type, and returns a map between those types. A
+// constant key may appear only once.
+func (a *Analyzer) visitMapLiteral(ml *ast.MapLiteral) types.Type {
+	key, elem := invalidType, invalidType
+	seen := make(map[string]bool)
+	arrayKeys := true
+
+	for i, kv := range ml.Pairs {
+		var keyOK, elemOK bool
+		key, keyOK = a.commonType(key, kv.Key, a.visitValue(kv.Key), i == 0, "map literal")
+		elem, elemOK = a.commonType(elem, kv.Value, a.visitValue(kv.Value), i == 0, "map literal")
+		if !keyOK || !elemOK {
+			return types.NewMap(invalidType, invalidType)
+		}
+
+		if _, ok := kv.Key.(*ast.ArrayLiteral); !ok {
+			arrayKeys = false
+		}
+		if value, ok := a.evalConst(kv.Key); ok {
+			if k := fmt.Sprint(value); seen[k] {
+				a.errf(kv.Key, "duplicate key %s in map literal", kv.Key)
+			} else {
+				seen[k] = true
+			}
+		}
+	}
+
+	// Array literal keys are slices only until the literal is used as a
+	// map with array keys; the key type is checked where it is inferred.
+	if !types.Comparable(key) && !arrayKeys {
+		a.errf(ml, "invalid map key type %s", types.Default(key))
+		key = invalidType
+	}
+
+	return types.NewMap(key, elem)
+}
+
+// checkMapLiteral checks a map literal used as a value of type target,
+// pair by pair. It reports false if target is not a map type, leaving the
+// literal to the ordinary rules.
+func (a *Analyzer) checkMapLiteral(ml *ast.MapLiteral, litType, target types.Type, context string) bool {
+	lit, ok := litType.(*types.Map)
+	if !ok {
+		return false
+	}
+
+	m, ok := target.Underlying().(*types.Map)
+	if !ok {
+		return false
+	}
+
+	for _, kv := range ml.Pairs {
+		a.checkAssignable(kv.Key, lit.Key, m.Key, context)
+		a.checkAssignable(kv.Value, lit.Elem, m.Elem, context)
+	}
+	return true
+}
+
+// visitMultiAssignment checks an assignment to several targets. The only
+// expression with more than one value is a map lookup, which in
+// v, ok = m[k] also yields whether the key is present.
+func (a *Analyzer) visitMultiAssignment(ma *ast.MultiAssignmentExpression) types.Type {
+	targetTypes := make([]types.Type, len(ma.Left))
+	var targets []*Symbol
+	for i, left := range ma.Left {
+		var target *Symbol
+		targetTypes[i], target = a.visitAssignTarget(left)
+		if target != nil {
+			targets = append(targets, target)
+		}
+	}
+
+	valueType, commaOK := invalidType, false
+	if ie, ok := ma.Value.(*ast.IndexExpression); ok {
+		valueType, commaOK = a.visitIndex(ie)
+	} else {
+		valueType = a.visitValue(ma.Value)
+	}
+
+	switch {
+	case commaOK && len(ma.Left) == 2:
+		a.checkAssignable(ma.Value, valueType, targetTypes[0], "assignment")
+		a.checkAssignable(ma.Value, boolType, targetTypes[1], "assignment")
+	case !types.IsInvalid(valueType):
+		a.errf(ma, "assignment mismatch: %d variables but 1 value", len(ma.Left))
+	}
+
+	for _, target := range targets {
+		delete(a.unassigned, target)
+	}
+
+	return voidType
+}
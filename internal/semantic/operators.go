@@ -0,0 +1,56 @@
+package semantic
+
+import "ixion/internal/types"
+
+// Shorthands for the basic types the analyzer refers to directly.
+var (
+	invalidType      types.Type = types.Typ[types.Invalid]
+	voidType         types.Type = types.Typ[types.Void]
+	boolType         types.Type = types.Typ[types.Bool]
+	intType          types.Type = types.Typ[types.Int]
+	stringType       types.Type = types.Typ[types.String]
+	untypedIntType   types.Type = types.Typ[types.UntypedInt]
+	untypedFloatType types.Type = types.Typ[types.UntypedFloat]
+)
+
+// comparisonOperators maps each comparison operator to whether it
+// requires ordered operands.
+var comparisonOperators = map[string]bool{
+	"==": false,
+	"!=": false,
+	"<":  true,
+	">":  true,
+	"<=": true,
+	">=": true,
+}
+
+// logicalOperators are the short-circuit boolean operators.
+var logicalOperators = map[string]bool{
+	"&&": true,
+	"||": true,
+}
+
+// arithmeticOperators are the binary arithmetic operators.
+var arithmeticOperators = map[string]bool{
+	"+": true,
+	"-": true,
+	"*": true,
+	"/": true,
+}
+
+// operandType returns the common type of the two operands of a binary
+// operator, converting an untyped operand to the type of the other one.
+func operandType(left, right types.Type) (types.Type, bool) {
+	switch {
+	case types.Identical(left, right):
+		return left, true
+	case types.IsUntyped(left) && types.IsUntyped(right):
+		return untypedFloatType, true
+	case types.IsUntyped(left) && types.AssignableTo(left, right):
+		return right, true
+	case types.IsUntyped(right) && types.AssignableTo(right, left):
+		return left, true
+	default:
+		return nil, false
+	}
+}
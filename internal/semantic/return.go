@@ -0,0 +1,81 @@
+package semantic
+
+import (
+	"ixion/internal/ast"
+	"ixion/internal/token"
+	"ixion/internal/types"
+)
+
+// checkMissingReturn reports "missing return" if control can reach the end
+// of the body of a function with a return type.
+func (a *Analyzer) checkMissingReturn(body *ast.BlockStatement, returnType types.Type) {
+	if body == nil || returnType == nil || isTerminating(body, "") {
+		return
+	}
+
+	// Point at the closing brace of the body.
+	end := body.Span().End
+	brace := end
+	brace.Offset--
+	brace.Column--
+	a.Errors = append(a.Errors, newError(token.NewSpan(brace, end), "missing return"))
+}
+
+// isTerminating reports whether stmt is a terminating statement, i.e. one
+// after which control never continues with the next statement. label is
+// the label attached to stmt, if any.
+func isTerminating(stmt ast.Statement, label string) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStatement:
+		return true
+	case *ast.BlockStatement:
+		if s == nil || len(s.Statements) == 0 {
+			return false
+		}
+		return isTerminating(s.Statements[len(s.Statements)-1], "")
+	case *ast.IfStatement:
+		if s.Alternative == nil {
+			return false
+		}
+		return isTerminating(s.Consequence, "") && isTerminating(s.Alternative, "")
+	case *ast.ForStatement:
+		// Only an infinite loop that is never broken out of terminates.
+		return s.Condition == nil && !hasBreak(s.Body, label, true)
+	case *ast.LabeledStatement:
+		return isTerminating(s.Statement, s.Label.Value)
+	default:
+		return false
+	}
+}
+
+// hasBreak reports whether stmt contains a break that leaves the loop
+// labeled label. implicit is true while an unlabeled break still refers to
+// that loop, that is, outside of any nested loop.
+func hasBreak(stmt ast.Statement, label string, implicit bool) bool {
+	switch s := stmt.(type) {
+	case *ast.BreakStatement:
+		if s.Label == nil {
+			return implicit
+		}
+		return s.Label.Value == label
+	case *ast.BlockStatement:
+		if s == nil {
+			return false
+		}
+		for _, inner := range s.Statements {
+			if hasBreak(inner, label, implicit) {
+				return true
+			}
+		}
+		return false
+	case *ast.IfStatement:
+		return hasBreak(s.Consequence, label, implicit) ||
+			(s.Alternative != nil && hasBreak(s.Alternative, label, implicit))
+	case *ast.ForStatement:
+		return label != "" && hasBreak(s.Body, label, false)
+	case *ast.LabeledStatement:
+		return hasBreak(s.Statement, label, implicit)
+	default:
+		return false
+	}
+}
@@ -2,14 +2,54 @@ package semantic
 
 import (
 	"fmt"
+	"slices"
 
 	"ixion/internal/ast"
+	"ixion/internal/types"
 )
 
+type SymbolKind int
+
+const (
+	VariableSymbol SymbolKind = iota
+	ConstantSymbol
+	FunctionSymbol
+	ParameterSymbol
+	TypeSymbol
+	BuiltinSymbol
+)
+
+var symbolKinds = map[SymbolKind]string{
+	VariableSymbol:  "variable",
+	ConstantSymbol:  "constant",
+	FunctionSymbol:  "function",
+	ParameterSymbol: "parameter",
+	TypeSymbol:      "type",
+	BuiltinSymbol:   "built-in function",
+}
+
+func (k SymbolKind) String() string {
+	return symbolKinds[k]
+}
+
 type Symbol struct {
 	Name  string
-	Type  string
+	Type  types.Type // *types.Func for a function, *types.Named for a type
+	Kind  SymbolKind
 	Scope *Scope
+
+	// Value holds the compile-time value of a constant: *big.Int,
+	// float64, string or bool. It is nil for every other kind of symbol.
+	Value any
+
+	// Decl is the identifier that declares the symbol.
+	Decl *ast.Identifier
+
+	// Reads counts the uses of the symbol as a value. Assignments to a
+	// variable are not reads.
+	Reads int
+
+	initializing bool // the initializer of the variable is being checked
 }
 
 type Scope struct {
@@ -18,19 +58,112 @@ type Scope struct {
 	Children []*Scope
 }
 
+// within reports whether s is outer or nested inside it.
+func (s *Scope) within(outer *Scope) bool {
+	for curr := s; curr != nil; curr = curr.Parent {
+		if curr == outer {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Scope) exist(name string) bool {
 	_, ok := s.Symbols[name]
 	return ok
 }
 
+// ShadowPolicy decides how the analyzer reports a declaration that hides
+// a symbol of an enclosing scope. Redeclaring a name in the same scope is
+// always an error.
+type ShadowPolicy int
+
+const (
+	ShadowAllow ShadowPolicy = iota // shadowing is not reported
+	ShadowWarn                      // shadowing is reported as a warning
+	ShadowError                     // shadowing is reported as an error
+)
+
+// label is a statement label visible to break and continue.
+type label struct {
+	name string
+	loop *loop // the labeled loop, or nil if the statement is not a loop
+}
+
+// loop describes a for statement whose body is being analyzed.
+type loop struct {
+	// breaks holds the unassigned variables at each break out of the loop.
+	breaks []assignState
+}
+
+// function describes the function whose body is being analyzed.
+type function struct {
+	name       string
+	returnType types.Type // nil if the function has no return type
+
+	literal *ast.FunctionLiteral // nil for a function declaration
+	scope   *Scope               // scope of the parameters
+	parent  *function            // enclosing function, if any
+}
+
+// String describes the function for error messages.
+func (f *function) String() string {
+	if f.literal != nil {
+		return "function literal"
+	}
+	return fmt.Sprintf("function '%s'", f.name)
+}
+
 type Analyzer struct {
 	CurrentScope *Scope
 	GlobalScope  *Scope
 	Errors       []error
+
+	// Warnings holds diagnostics for code that is suspicious but valid,
+	// such as unused variables. They do not make the analysis fail.
+	Warnings []error
+
+	// Shadowing decides how a declaration that hides a name of an
+	// enclosing scope is reported. It is allowed by default.
+	Shadowing ShadowPolicy
+
+	// Captures lists, for each function literal, the variables and
+	// parameters of enclosing functions that the literal refers to, in
+	// order of first use. A literal that captures nothing has no entry.
+	Captures map[*ast.FunctionLiteral][]*Symbol
+
+	loops    []*loop  // loops enclosing the current statement, innermost last
+	labels   []*label // labels enclosing the current statement, innermost last
+	function *function
+
+	// unassigned holds the variables without a value at the current
+	// statement.
+	unassigned assignState
+
+	// pendingTypes holds the declared types whose underlying type is not
+	// resolved yet, and resolvingTypes those being resolved.
+	pendingTypes   map[*types.Named]*ast.TypeDeclaration
+	resolvingTypes map[*types.Named]bool
+
+	// indirect counts the slice and function types enclosing the type
+	// being resolved. Inside them a declared type may refer to itself.
+	indirect int
+
+	// consts caches the result of evaluating each expression at compile
+	// time, so that errors found while evaluating are reported only once.
+	consts map[ast.Expression]constant
+}
+
+// constant is the result of evaluating an expression at compile time. ok
+// is false if the expression is not constant.
+type constant struct {
+	value any
+	ok    bool
 }
 
 func NewAnalyzer() *Analyzer {
 	globalScope := &Scope{
+		Parent:  newUniverse(),
 		Symbols: make(map[string]*Symbol),
 	}
 
@@ -38,21 +171,38 @@ func NewAnalyzer() *Analyzer {
 		CurrentScope: globalScope,
 		GlobalScope:  globalScope,
 		Errors:       []error(nil),
+		Captures:     make(map[*ast.FunctionLiteral][]*Symbol),
+		unassigned:   assignState{},
+		consts:       make(map[ast.Expression]constant),
+
+		pendingTypes:   make(map[*types.Named]*ast.TypeDeclaration),
+		resolvingTypes: make(map[*types.Named]bool),
 	}
 }
 
+// Analyze checks program and returns its errors. Warnings are collected
+// in a.Warnings.
 func (a *Analyzer) Analyze(program *ast.Program) []error {
 	a.visitProgram(program)
+	a.checkUnused(a.GlobalScope)
+
+	slices.SortStableFunc(a.Warnings, func(x, y error) int {
+		return x.(*SemanticError).Pos().Offset - y.(*SemanticError).Pos().Offset
+	})
 
 	return a.Errors
 }
 
 func (a *Analyzer) err(node ast.Node, args ...any) {
-	a.Errors = append(a.Errors, fmt.Errorf("%s: %s", node.TokenLiteral(), fmt.Sprint(args...)))
+	a.Errors = append(a.Errors, newError(node.Span(), fmt.Sprint(args...)))
 }
 
 func (a *Analyzer) errf(node ast.Node, format string, args ...any) {
-	a.Errors = append(a.Errors, fmt.Errorf("%s: %s", node.TokenLiteral(), fmt.Sprintf(format, args...)))
+	a.Errors = append(a.Errors, newError(node.Span(), fmt.Sprintf(format, args...)))
+}
+
+func (a *Analyzer) warnf(node ast.Node, format string, args ...any) {
+	a.Warnings = append(a.Warnings, newWarning(node.Span(), fmt.Sprintf(format, args...)))
 }
 
 func (a *Analyzer) enterScope() {
@@ -66,11 +216,114 @@ func (a *Analyzer) enterScope() {
 }
 
 func (a *Analyzer) exitScope() {
+	a.checkUnused(a.CurrentScope)
+
 	if a.CurrentScope.Parent != nil {
 		a.CurrentScope = a.CurrentScope.Parent
 	}
 }
 
+// enterFunction starts the analysis of the body of fn and opens the scope
+// of its parameters. Loops and labels of the enclosing code do not reach
+// into the body. The returned function restores the enclosing context.
+//
+// A function literal sees the variables its enclosing code has assigned
+// so far, but its own assignments may never run. A declared function may
+// be called at any time, so it treats every outer variable as assigned.
+func (a *Analyzer) enterFunction(fn *function) (restore func()) {
+	loops, labels, parent, unassigned := a.loops, a.labels, a.function, a.unassigned
+
+	if fn.literal != nil {
+		a.unassigned = unassigned.clone()
+	} else {
+		a.unassigned = assignState{}
+	}
+
+	a.enterScope()
+	fn.scope, fn.parent = a.CurrentScope, parent
+	a.loops, a.labels, a.function = nil, nil, fn
+
+	return func() {
+		a.exitScope()
+		a.loops, a.labels, a.function, a.unassigned = loops, labels, parent, unassigned
+	}
+}
+
+func (a *Analyzer) lookupLabel(name string) *label {
+	for i := len(a.labels) - 1; i >= 0; i-- {
+		if a.labels[i].name == name {
+			return a.labels[i]
+		}
+	}
+
+	return nil
+}
+
+// use records a read of symbol at node.
+func (a *Analyzer) use(node ast.Node, symbol *Symbol) {
+	a.checkRead(node, symbol)
+	a.capture(symbol)
+	symbol.Reads++
+}
+
+// checkUnused warns about the symbols of scope that are never read. Only
+// functions are reported in the global scope, since global variables may
+// be meant for code outside the program.
+func (a *Analyzer) checkUnused(scope *Scope) {
+	var unused []*Symbol
+	for _, symbol := range scope.Symbols {
+		if symbol.Reads > 0 || symbol.Decl == nil || symbol.Kind == ConstantSymbol || symbol.Kind == TypeSymbol {
+			continue
+		}
+		if scope == a.GlobalScope && symbol.Kind != FunctionSymbol {
+			continue
+		}
+		unused = append(unused, symbol)
+	}
+
+	slices.SortFunc(unused, func(x, y *Symbol) int {
+		return x.Decl.Span().Start.Offset - y.Decl.Span().Start.Offset
+	})
+
+	for _, symbol := range unused {
+		a.warnf(symbol.Decl, "%s '%s' declared and not used", symbol.Kind, symbol.Name)
+	}
+}
+
+// checkShadow reports the declaration ident hiding the symbol outer of an
+// enclosing scope, unless shadowing is allowed.
+func (a *Analyzer) checkShadow(ident *ast.Identifier, outer *Symbol) {
+	if outer.Decl == nil {
+		return
+	}
+
+	const format = "declaration of '%s' shadows %s declared at %s"
+	switch a.Shadowing {
+	case ShadowWarn:
+		a.warnf(ident, format, ident.Value, outer.Kind, outer.Decl.Span().Start)
+	case ShadowError:
+		a.errf(ident, format, ident.Value, outer.Kind, outer.Decl.Span().Start)
+	}
+}
+
+// capture records symbol as captured by every function literal between
+// the current function and the scope that declares symbol. Globals,
+// constants and functions are not captured.
+func (a *Analyzer) capture(symbol *Symbol) {
+	if symbol.Scope == a.GlobalScope || (symbol.Kind != VariableSymbol && symbol.Kind != ParameterSymbol) {
+		return
+	}
+
+	for fn := a.function; fn != nil && fn.literal != nil; fn = fn.parent {
+		if symbol.Scope.within(fn.scope) {
+			return
+		}
+		if !slices.Contains(a.Captures[fn.literal], symbol) {
+			a.Captures[fn.literal] = append(a.Captures[fn.literal], symbol)
+		}
+	}
+}
+
 func (a *Analyzer) resolve(name string) *Symbol {
 	curr := a.CurrentScope
 
@@ -84,15 +337,27 @@ func (a *Analyzer) resolve(name string) *Symbol {
 	return nil
 }
 
-func (a *Analyzer) declare(name string, _type string) bool {
-	if a.CurrentScope.exist(name) {
-		return false
+// declare adds a symbol declared by ident to the current scope. It
+// returns nil if the name is already declared in that scope. A symbol of
+// an enclosing scope with the same name is shadowed, which is reported
+// according to a.Shadowing.
+func (a *Analyzer) declare(ident *ast.Identifier, _type types.Type, kind SymbolKind) *Symbol {
+	if a.CurrentScope.exist(ident.Value) {
+		return nil
+	}
+
+	if outer := a.resolve(ident.Value); outer != nil {
+		a.checkShadow(ident, outer)
 	}
 
-	a.CurrentScope.Symbols[name] = &Symbol{
-		Name: name,
-		Type: _type,
+	symbol := &Symbol{
+		Name:  ident.Value,
+		Type:  _type,
+		Kind:  kind,
+		Scope: a.CurrentScope,
+		Decl:  ident,
 	}
+	a.CurrentScope.Symbols[ident.Value] = symbol
 
-	return true
+	return symbol
 }
@@ -0,0 +1,57 @@
+package semantic
+
+import (
+	"fmt"
+
+	"ixion/internal/token"
+)
+
+// Severity tells whether a diagnostic makes the program invalid.
+type Severity int
+
+const (
+	// Error is a diagnostic that makes the program invalid.
+	Error Severity = iota
+	// Warning is a diagnostic for suspicious but valid code.
+	Warning
+)
+
+var severities = map[Severity]string{
+	Error:   "error",
+	Warning: "warning",
+}
+
+func (s Severity) String() string {
+	return severities[s]
+}
+
+type SemanticError struct {
+	Severity Severity
+	Span     token.Span
+	Message  string
+}
+
+func newError(span token.Span, msg string) error {
+	return &SemanticError{
+		Severity: Error,
+		Span:     span,
+		Message:  msg,
+	}
+}
+
+func newWarning(span token.Span, msg string) error {
+	return &SemanticError{
+		Severity: Warning,
+		Span:     span,
+		Message:  msg,
+	}
+}
+
+// Pos returns the position the error starts at.
+func (s *SemanticError) Pos() token.Pos {
+	return s.Span.Start
+}
+
+func (s *SemanticError) Error() string {
+	return fmt.Sprintf("semantic %s at %s: %s", s.Severity, s.Span.Start, s.Message)
+}
@@ -0,0 +1,1297 @@
+package semantic_test
+
+import (
+	"testing"
+
+	"ixion/internal/lexer"
+	"ixion/internal/parser"
+	"ixion/internal/semantic"
+	"ixion/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyze(t *testing.T, input string) []string {
+	t.Helper()
+
+	toks, err := lexer.Tokenize(input)
+	require.NoError(t, err)
+
+	p := parser.New(toks)
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	var msgs []string
+	for _, err := range semantic.NewAnalyzer().Analyze(program) {
+		msgs = append(msgs, err.Error())
+	}
+
+	return msgs
+}
+
+func TestAnalyzer_Operators(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "comparisons yield bool",
+			input: "var a = 1 < 2; var b = a == true && \"x\" != \"y\";",
+		},
+		{
+			name:  "mismatched comparison",
+			input: "var n = 1; var a = n == \"x\";",
+			want:  []string{"semantic error at 1:20: mismatched types int and string in comparison '=='"},
+		},
+		{
+			name:  "unordered comparison",
+			input: "var a = true < false;",
+			want:  []string{"semantic error at 1:9: operator '<' is not defined on bool"},
+		},
+		{
+			name:  "non-bool logical operand",
+			input: "var n = 1; var a = n && true;",
+			want:  []string{"semantic error at 1:20: operator '&&' requires bool operand, got int"},
+		},
+		{
+			name:  "non-bool negation",
+			input: "var a = !\"x\";",
+			want:  []string{"semantic error at 1:10: operator '!' requires bool operand, got string"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_Statements(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "if branches have their own scope",
+			input: "var a = 1; if a > 0 { var b = 1; } else { var b = 2; } print(b);",
+			want:  []string{"semantic error at 1:62: undeclared variable 'b'"},
+		},
+		{
+			name:  "non-bool if condition",
+			input: "var a = 1; if a { print(a); } else if \"x\" { print(a); }",
+			want: []string{
+				"semantic error at 1:15: non-bool int used as condition",
+				"semantic error at 1:39: non-bool string used as condition",
+			},
+		},
+		{
+			name:  "loop variables are scoped to the loop",
+			input: "for var i = 0; i < 3; i = i + 1 { print(i); } print(i);",
+			want:  []string{"semantic error at 1:53: undeclared variable 'i'"},
+		},
+		{
+			name:  "non-bool loop condition",
+			input: "var n = 3; for n { n = n - 1; }",
+			want:  []string{"semantic error at 1:16: non-bool int used as condition"},
+		},
+		{
+			name:  "labeled break and continue",
+			input: "outer: for { for { if true { continue outer; } break outer; } }",
+		},
+		{
+			name:  "break outside loop",
+			input: "for { fn f() { break; } } continue;",
+			want: []string{
+				"semantic error at 1:16: break is not in a loop",
+				"semantic error at 1:27: continue is not in a loop",
+			},
+		},
+		{
+			name:  "unknown and non-loop labels",
+			input: "for { break nope; } block: if true { for { continue block; } }",
+			want: []string{
+				"semantic error at 1:13: break label not defined: 'nope'",
+				"semantic error at 1:53: invalid continue label 'block': not a loop",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_Constants(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "constant expressions",
+			input: "const a = 2; const b int = a * 10 - 1; const c = \"x\" + \"y\"; const d = b > a && true;",
+		},
+		{
+			name:  "non-constant initializer",
+			input: "var v = 1; const c = v + 1;",
+			want:  []string{"semantic error at 1:22: const initializer (v + 1) is not a constant expression"},
+		},
+		{
+			name:  "division by zero",
+			input: "const c = 1 / (2 - 2);",
+			want:  []string{"semantic error at 1:11: division by zero in constant expression"},
+		},
+		{
+			name:  "division by zero in untyped use",
+			input: "print(1 / 0); var x = 2.5 / 0;",
+			want: []string{
+				"semantic error at 1:7: division by zero in constant expression",
+				"semantic error at 1:23: division by zero in constant expression",
+			},
+		},
+		{
+			name:  "mixed int and float operands",
+			input: "const c = 1 + 1.5; const d = 1.5 * 2; const e float = c / 2 - d; const f = 3 > 2.5;",
+		},
+		{
+			name:  "float division by integer zero",
+			input: "const c = 1.0 / 0;",
+			want:  []string{"semantic error at 1:11: division by zero in constant expression"},
+		},
+		{
+			name:  "mismatched declared type",
+			input: "const c string = 1;",
+			want:  []string{"semantic error at 1:18: cannot use 1 (untyped int constant) as string value in constant declaration"},
+		},
+		{
+			name:  "assignment to constant",
+			input: "const c = 1; c = 2;",
+			want:  []string{"semantic error at 1:14: cannot assign to constant 'c'"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_ExpressionTypes(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "well-typed expressions",
+			input: `var a int8 = 1; var b = a * 2 - -a; var f float = 1; var g = f / 2.5;
+				var s = "a" + "b"; var ok bool = b > a && s != "c";
+				fn inc(x int) int { return x + 1; } var r int = inc(1) + 2;`,
+		},
+		{
+			name:  "mismatched operand types",
+			input: `var a = "a" - 1;`,
+			want:  []string{"semantic error at 1:9: mismatched types string and untyped int in operation '-'"},
+		},
+		{
+			name:  "operator not defined on type",
+			input: `var a = "a" * "b"; var b = -true; var c = true + false;`,
+			want: []string{
+				"semantic error at 1:9: operator '*' is not defined on string",
+				"semantic error at 1:28: operator '-' is not defined on bool",
+				"semantic error at 1:43: operator '+' is not defined on bool",
+			},
+		},
+		{
+			name:  "declared type mismatch",
+			input: `var a int = "x"; var b string = 1 + 2; var c bool = 1 < 2;`,
+			want: []string{
+				"semantic error at 1:13: cannot use \"x\" (type string) as int value in variable declaration",
+				"semantic error at 1:33: cannot use (1 + 2) (type untyped int) as string value in variable declaration",
+			},
+		},
+		{
+			name:  "assignment type mismatch",
+			input: `var a = 1; a = "x"; var f float = 1.5; f = 2;`,
+			want:  []string{"semantic error at 1:16: cannot use \"x\" (type string) as int value in assignment"},
+		},
+		{
+			name:  "call result types",
+			input: `fn s() string { return "x"; } fn v() { print(1); } var a int = s(); var b = v();`,
+			want: []string{
+				"semantic error at 1:64: cannot use s() (type string) as int value in variable declaration",
+				"semantic error at 1:77: v() (no value) used as value",
+			},
+		},
+		{
+			name:  "mixed integer types",
+			input: `var a int8 = 1; var b int64 = 2; var c = a + b;`,
+			want:  []string{"semantic error at 1:42: mismatched types int8 and int64 in operation '+'"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_ReturnTypes(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "matching returns",
+			input: "fn abs(x int) int { if x < 0 { return -x; } return x; } fn log(s string) { print(s); return; }",
+		},
+		{
+			name:  "wrong return type",
+			input: "fn f() int { return \"x\"; }",
+			want:  []string{"semantic error at 1:21: cannot use \"x\" (type string) as int value in return statement"},
+		},
+		{
+			name:  "value returned from function without return type",
+			input: "fn f() { return 1; }",
+			want:  []string{"semantic error at 1:17: unexpected return value: function 'f' has no return type"},
+		},
+		{
+			name:  "bare return in typed function",
+			input: "fn f() string { return; }",
+			want:  []string{"semantic error at 1:17: missing return value: function 'f' returns string"},
+		},
+		{
+			name:  "return outside function",
+			input: "return 1;",
+			want:  []string{"semantic error at 1:1: return statement outside function"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_MissingReturn(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "empty body",
+			input: "fn f() int { }",
+			want:  []string{"semantic error at 1:14: missing return"},
+		},
+		{
+			name:  "if without else",
+			input: "fn f(x int) int {\n  if x > 0 { return 1; }\n}",
+			want:  []string{"semantic error at 3:1: missing return"},
+		},
+		{
+			name:  "if else returning on both paths",
+			input: "fn f(x int) int { if x > 0 { return 1; } else { return 2; } }",
+		},
+		{
+			name:  "else if chain without final else",
+			input: "fn f(x int) int { if x > 0 { return 1; } else if x < 0 { return 2; } }",
+			want:  []string{"semantic error at 1:70: missing return"},
+		},
+		{
+			name:  "else if chain with final else",
+			input: "fn f(x int) int { if x > 0 { return 1; } else if x < 0 { return 2; } else { return 0; } }",
+		},
+		{
+			name:  "one branch falls through",
+			input: "fn f(x int) int { if x > 0 { return 1; } else { print(x); } }",
+			want:  []string{"semantic error at 1:61: missing return"},
+		},
+		{
+			name:  "infinite loop",
+			input: "fn f() int { for { } }",
+		},
+		{
+			name:  "infinite loop with break",
+			input: "fn f() int { for { break; } }",
+			want:  []string{"semantic error at 1:29: missing return"},
+		},
+		{
+			name:  "break from inner loop only",
+			input: "fn f() int { for { for { break; } } }",
+		},
+		{
+			name:  "labeled break from inner loop",
+			input: "fn f() int { outer: for { for { break outer; } } }",
+			want:  []string{"semantic error at 1:50: missing return"},
+		},
+		{
+			name:  "loop with condition",
+			input: "fn f(x int) int { for x > 0 { return x; } }",
+			want:  []string{"semantic error at 1:43: missing return"},
+		},
+		{
+			name:  "function without return type",
+			input: "fn f(x int) { if x > 0 { return; } }",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_SizedIntegers(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "literals within range",
+			input: "var a int8 = -128; var b uint8 = 255; var c int64 = 9223372036854775807;",
+		},
+		{
+			name:  "literals beyond int64",
+			input: "var a uint64 = 18446744073709551615; var b uint64 = 0xFFFF_FFFF_FFFF_FFFF; var c int64 = -9223372036854775808;",
+		},
+		{
+			name:  "literal overflows uint64",
+			input: "var x uint64 = 18446744073709551616;",
+			want:  []string{"semantic error at 1:16: cannot use 18446744073709551616 (untyped int constant 18446744073709551616) as uint64 value in variable declaration (overflows)"},
+		},
+		{
+			name:  "literal overflows declared type",
+			input: "var x int8 = 1000;",
+			want:  []string{"semantic error at 1:14: cannot use 1000 (untyped int constant 1000) as int8 value in variable declaration (overflows)"},
+		},
+		{
+			name:  "negative value for unsigned type",
+			input: "var x uint8 = -1;",
+			want:  []string{"semantic error at 1:15: cannot use (-1) (untyped int constant -1) as uint8 value in variable declaration (overflows)"},
+		},
+		{
+			name:  "untyped constant overflows int",
+			input: "const big = 9223372036854775807 + 1; var x = big;",
+			want:  []string{"semantic error at 1:46: cannot use big (untyped int constant 9223372036854775808) as int value in variable declaration (overflows)"},
+		},
+		{
+			name:  "typed constant declaration overflows",
+			input: "const c uint16 = 70000;",
+			want:  []string{"semantic error at 1:18: cannot use 70000 (untyped int constant 70000) as uint16 value in constant declaration (overflows)"},
+		},
+		{
+			name:  "typed constant expression overflows",
+			input: "const a int8 = 100; const b = a * 2;",
+			want:  []string{"semantic error at 1:31: constant 200 overflows int8"},
+		},
+		{
+			name:  "untyped operand overflows other operand's type",
+			input: "var x int8 = 1; var y = x + 300;",
+			want:  []string{"semantic error at 1:29: 300 (untyped int constant 300) overflows int8"},
+		},
+		{
+			name:  "mixed integer types",
+			input: "var a uint8 = 1; var b int64 = 2; var c = a + b;",
+			want:  []string{"semantic error at 1:43: mismatched types uint8 and int64 in operation '+'"},
+		},
+		{
+			name:  "no implicit conversion on assignment",
+			input: "var a int32 = 1; var b int64 = a;",
+			want:  []string{"semantic error at 1:32: cannot use a (type int32) as int64 value in variable declaration"},
+		},
+		{
+			name:  "explicit conversion",
+			input: "var a int32 = 1; var b int64 = int64(a); var c = int8(a) + int8(1); var d float = float(a);",
+		},
+		{
+			name:  "constant conversion overflows",
+			input: "var x = int8(1000);",
+			want:  []string{"semantic error at 1:9: constant 1000 overflows int8"},
+		},
+		{
+			name:  "constant float conversion truncated",
+			input: "var x = int(1.5); var y = int(2.0);",
+			want:  []string{"semantic error at 1:9: cannot convert 1.5 (untyped float constant 1.5) to type int (truncated)"},
+		},
+		{
+			name:  "invalid conversion",
+			input: "var s = \"1\"; var x = int(s);",
+			want:  []string{"semantic error at 1:22: cannot convert s (type string) to type int"},
+		},
+		{
+			name:  "conversion result is typed",
+			input: "var x = uint8(1); var y int = x;",
+			want:  []string{"semantic error at 1:31: cannot use x (type uint8) as int value in variable declaration"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_Calls(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "matching call",
+			input: "fn f(a int, s string) bool { return a > 0; } var ok bool = f(1, \"x\");",
+		},
+		{
+			name:  "not enough arguments",
+			input: "fn f(a int, s string) { } f(1);",
+			want:  []string{"semantic error at 1:27: not enough arguments in call to 'f': have 1, want 2"},
+		},
+		{
+			name:  "too many arguments",
+			input: "fn f() { } f(1, 2);",
+			want:  []string{"semantic error at 1:12: too many arguments in call to 'f': have 2, want 0"},
+		},
+		{
+			name:  "wrong argument type",
+			input: "fn f(a int, s string) { } f(\"x\", 1);",
+			want: []string{
+				"semantic error at 1:29: cannot use \"x\" (type string) as int value in argument to 'f'",
+				"semantic error at 1:34: cannot use 1 (type untyped int) as string value in argument to 'f'",
+			},
+		},
+		{
+			name:  "argument overflows parameter type",
+			input: "fn f(b uint8) { } f(256);",
+			want:  []string{"semantic error at 1:21: cannot use 256 (untyped int constant 256) as uint8 value in argument to 'f' (overflows)"},
+		},
+		{
+			name:  "call has the function's return type",
+			input: "fn f() string { return \"x\"; } var n int = f();",
+			want:  []string{"semantic error at 1:43: cannot use f() (type string) as int value in variable declaration"},
+		},
+		{
+			name:  "call through function variable",
+			input: "fn add(a int, b int) int { return a + b; } var f = add; var s string = f(1);",
+			want: []string{
+				"semantic error at 1:72: not enough arguments in call to 'f': have 1, want 2",
+				"semantic error at 1:72: cannot use f(1) (type int) as string value in variable declaration",
+			},
+		},
+		{
+			name:  "call of non-function",
+			input: "var n = 1; n();",
+			want:  []string{"semantic error at 1:12: 'n' is not a function"},
+		},
+		{
+			name:  "function literal call",
+			input: "var n int = fn(a int) int { return a; }(true);",
+			want:  []string{"semantic error at 1:41: cannot use true (type bool) as int value in argument to 'function literal'"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_FunctionSignature(t *testing.T) {
+	toks, err := lexer.Tokenize("fn f(a int, s string) bool { return true; } fn g() { }")
+	require.NoError(t, err)
+
+	p := parser.New(toks)
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	analyzer := semantic.NewAnalyzer()
+	require.Empty(t, analyzer.Analyze(program))
+
+	f, ok := analyzer.GlobalScope.Symbols["f"].Type.(*types.Func)
+	require.True(t, ok)
+	assert.Equal(t, []types.Type{types.Typ[types.Int], types.Typ[types.String]}, f.Params)
+	assert.Equal(t, types.Typ[types.Bool], f.Result)
+	assert.Equal(t, "fn(int, string) bool", f.String())
+
+	assert.Equal(t, "fn()", analyzer.GlobalScope.Symbols["g"].Type.String())
+}
+
+func TestAnalyzer_FunctionTypes(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "callback parameter",
+			input: "fn apply(f fn(int) int, x int) int { return f(x); }\n" +
+				"fn double(x int) int { return x * 2; }\n" +
+				"var n int = apply(double, 1);",
+		},
+		{
+			name: "callback with wrong signature",
+			input: "fn apply(f fn(int) int, x int) int { return f(x); }\n" +
+				"fn greet(s string) string { return s; }\n" +
+				"apply(greet, 1);",
+			want: []string{"semantic error at 3:7: cannot use greet (type fn(string) string) as fn(int) int value in argument to 'apply'"},
+		},
+		{
+			name:  "callback called with wrong argument",
+			input: "fn apply(f fn(int)) { f(\"x\"); }",
+			want:  []string{"semantic error at 1:25: cannot use \"x\" (type string) as int value in argument to 'f'"},
+		},
+		{
+			name:  "function type return value",
+			input: "fn id(x int) int { return x; } fn pick() fn(int) int { return id; } var n int = pick()(1);",
+		},
+		{
+			name:  "variable of function type",
+			input: "fn id(x int) int { return x; } var f fn(int) int = id; var g fn() = id;",
+			want:  []string{"semantic error at 1:69: cannot use id (type fn(int) int) as fn() value in variable declaration"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_FunctionLiterals(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "parameters are declared",
+			input: "var f = fn(a int, b int) int { return a + b; };",
+		},
+		{
+			name:  "body is scope-checked",
+			input: "var f = fn() { print(missing); };",
+			want:  []string{"semantic error at 1:22: undeclared variable 'missing'"},
+		},
+		{
+			name:  "parameters do not leak",
+			input: "var f = fn(a int) { }; print(a);",
+			want:  []string{"semantic error at 1:30: undeclared variable 'a'"},
+		},
+		{
+			name:  "return checked against literal",
+			input: "var f = fn() int { return \"x\"; };",
+			want:  []string{"semantic error at 1:27: cannot use \"x\" (type string) as int value in return statement"},
+		},
+		{
+			name:  "unexpected return value",
+			input: "var f = fn() { return 1; };",
+			want:  []string{"semantic error at 1:23: unexpected return value: function literal has no return type"},
+		},
+		{
+			name:  "missing return",
+			input: "var f = fn(x int) int { if x > 0 { return x; } };",
+			want:  []string{"semantic error at 1:48: missing return"},
+		},
+		{
+			name:  "loops do not reach into literal",
+			input: "for { var f = fn() { break; }; }",
+			want:  []string{"semantic error at 1:22: break is not in a loop"},
+		},
+		{
+			name:  "literal type",
+			input: "var f = fn(x int) bool { return x > 0; }; var ok bool = f(1); var n int = f(2);",
+			want:  []string{"semantic error at 1:75: cannot use f(2) (type bool) as int value in variable declaration"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_Captures(t *testing.T) {
+	input := `
+var global = 1;
+fn counter(start int) fn() int {
+	var count = start;
+	var step = 1;
+	var inc = fn() int {
+		count = count + step;
+		var local = global;
+		return count;
+	};
+	var nested = fn() fn() int {
+		return fn() int { return start; };
+	};
+	var pure = fn(x int) int { return x * 2; };
+	return inc;
+}
+`
+	toks, err := lexer.Tokenize(input)
+	require.NoError(t, err)
+
+	p := parser.New(toks)
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	analyzer := semantic.NewAnalyzer()
+	require.Empty(t, analyzer.Analyze(program))
+
+	// Literals are identified by the line they start on.
+	captures := map[int][]string{}
+	for lit, symbols := range analyzer.Captures {
+		for _, symbol := range symbols {
+			captures[lit.Span().Start.Line] = append(captures[lit.Span().Start.Line], symbol.Name)
+		}
+	}
+
+	assert.Equal(t, map[int][]string{
+		6:  {"count", "step"},
+		11: {"start"},
+		12: {"start"},
+	}, captures)
+}
+
+func TestAnalyzer_Hoisting(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "call before declaration",
+			input: "var n int = twice(2); fn twice(x int) int { return x * 2; }",
+		},
+		{
+			name: "mutual recursion",
+			input: "fn even(n int) bool { if n == 0 { return true; } return odd(n - 1); }\n" +
+				"fn odd(n int) bool { if n == 0 { return false; } return even(n - 1); }",
+		},
+		{
+			name:  "hoisted call is type-checked",
+			input: "var s string = later(1); fn later(x int) int { return x; }",
+			want:  []string{"semantic error at 1:16: cannot use later(1) (type int) as string value in variable declaration"},
+		},
+		{
+			name:  "duplicate function reported once",
+			input: "fn f() { } fn f() { }",
+			want:  []string{"semantic error at 1:15: function 'f' already declare"},
+		},
+		{
+			name:  "global variables keep source order",
+			input: "print(y); var y = 1;",
+			want:  []string{"semantic error at 1:7: undeclared variable 'y'"},
+		},
+		{
+			name:  "local variables keep source order",
+			input: "fn f() { print(x); var x = 1; }",
+			want:  []string{"semantic error at 1:16: undeclared variable 'x'"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_Initialization(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "variable in its own initializer",
+			input: "var a = a + 1;",
+			want:  []string{"semantic error at 1:9: variable 'a' used in its own initializer"},
+		},
+		{
+			name:  "own initializer shadows outer variable",
+			input: "fn f(a int) { if true { var b int = b * 2; } }",
+			want:  []string{"semantic error at 1:37: variable 'b' used in its own initializer"},
+		},
+		{
+			name:  "recursive closure in initializer",
+			input: "var f = fn(n int) int { return f(n - 1); };",
+			want:  []string{"semantic error at 1:32: variable 'f' used in its own initializer"},
+		},
+		{
+			name:  "declared then assigned",
+			input: "fn f() int { var x int; x = 1; return x; }",
+		},
+		{
+			name:  "read before assignment",
+			input: "fn f() int { var x int; return x; }",
+			want:  []string{"semantic error at 1:32: variable 'x' is used before being assigned"},
+		},
+		{
+			name:  "assigned in its own assignment",
+			input: "fn f() { var x int; x = x + 1; }",
+			want:  []string{"semantic error at 1:25: variable 'x' is used before being assigned"},
+		},
+		{
+			name:  "assigned on both branches",
+			input: "fn f(c bool) int { var x int; if c { x = 1; } else { x = 2; } return x; }",
+		},
+		{
+			name:  "assigned on one branch",
+			input: "fn f(c bool) int { var x int; if c { x = 1; } return x; }",
+			want:  []string{"semantic error at 1:54: variable 'x' is used before being assigned"},
+		},
+		{
+			name:  "other branch returns",
+			input: "fn f(c bool) int { var x int; if c { x = 1; } else { return 0; } return x; }",
+		},
+		{
+			name:  "else if chain without final else",
+			input: "fn f(n int) int { var x int; if n > 0 { x = 1; } else if n < 0 { x = 2; } return x; }",
+			want:  []string{"semantic error at 1:82: variable 'x' is used before being assigned"},
+		},
+		{
+			name:  "assigned only inside loop",
+			input: "fn f(n int) int { var x int; for n > 0 { x = n; n = n - 1; } return x; }",
+			want:  []string{"semantic error at 1:69: variable 'x' is used before being assigned"},
+		},
+		{
+			name:  "assigned before break in loop without condition",
+			input: "fn f() int { var a int; for { a = 1; break; } return a; }",
+		},
+		{
+			name:  "break before assignment in loop without condition",
+			input: "fn f(c bool) int { var a int; for { if c { break; } a = 1; break; } return a; }",
+			want:  []string{"semantic error at 1:76: variable 'a' is used before being assigned"},
+		},
+		{
+			name:  "labeled break out of nested loop",
+			input: "fn f(n int) int { var a int; outer: for { for n > 0 { a = n; break outer; } } return a; }",
+		},
+		{
+			name:  "assigned inside function literal",
+			input: "fn f() int { var x int; var set = fn() { x = 1; }; set(); return x; }",
+			want:  []string{"semantic error at 1:66: variable 'x' is used before being assigned"},
+		},
+		{
+			name:  "read in function literal",
+			input: "fn f() { var x int; var get = fn() int { return x; }; }",
+			want:  []string{"semantic error at 1:49: variable 'x' is used before being assigned"},
+		},
+		{
+			name:  "global read in function body",
+			input: "var total int; fn add(n int) { total = total + n; } total = 0;",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_UnusedWarnings(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "everything used",
+			input: "fn add(a int, b int) int { var sum = a + b; return sum; } print(add(1, 2));",
+		},
+		{
+			name:  "unused local variable",
+			input: "fn f() { var x = 1; }\nf();",
+			want:  []string{"semantic warning at 1:14: variable 'x' declared and not used"},
+		},
+		{
+			name:  "assigned but never read",
+			input: "fn f() { var x int; x = 1; }\nf();",
+			want:  []string{"semantic warning at 1:14: variable 'x' declared and not used"},
+		},
+		{
+			name:  "unused parameter",
+			input: "fn f(a int, b int) int { return a; }\nprint(f(1, 2));",
+			want:  []string{"semantic warning at 1:13: parameter 'b' declared and not used"},
+		},
+		{
+			name:  "unused top-level function",
+			input: "fn helper() { }",
+			want:  []string{"semantic warning at 1:4: function 'helper' declared and not used"},
+		},
+		{
+			name:  "use through a closure",
+			input: "fn f() int { var x = 1; var g = fn() int { return x; }; return g(); }\nprint(f());",
+		},
+		{
+			name:  "unused loop variable and literal parameter",
+			input: "for var i = 0; true; { }\nvar g = fn(n int) { };\ng(1);",
+			want: []string{
+				"semantic warning at 1:9: variable 'i' declared and not used",
+				"semantic warning at 2:12: parameter 'n' declared and not used",
+			},
+		},
+		{
+			name:  "global variables and constants are not reported",
+			input: "var unused = 1; const limit = 10;",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			toks, err := lexer.Tokenize(tt.input)
+			require.NoError(t, err)
+
+			p := parser.New(toks)
+			program := p.ParseProgram()
+			require.Empty(t, p.Errors())
+
+			analyzer := semantic.NewAnalyzer()
+			require.Empty(t, analyzer.Analyze(program))
+
+			var warnings []string
+			for _, warning := range analyzer.Warnings {
+				warnings = append(warnings, warning.Error())
+			}
+			assert.Equal(t, tt.want, warnings)
+		})
+	}
+}
+
+func TestAnalyzer_Shadowing(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   semantic.ShadowPolicy
+		input    string
+		errors   []string
+		warnings []string
+	}{
+		{
+			name:  "shadowing allowed by default",
+			input: "var x = 1; fn f() { var x = \"s\"; print(x); }\nf(); print(x);",
+		},
+		{
+			name:   "redeclaration in same scope reported once",
+			input:  "fn f() { var x = 1; var x = 2; print(x); }\nf();",
+			errors: []string{"semantic error at 1:25: variable 'x' already declare"},
+		},
+		{
+			name:   "parameter redeclared in function body",
+			input:  "fn f(x int) { var x = 1; print(x); }\nf(1);",
+			errors: []string{"semantic error at 1:19: variable 'x' already declare"},
+		},
+		{
+			name:   "parameter redeclared in function literal",
+			policy: semantic.ShadowWarn,
+			input:  "var g = fn(x int) { var x = 1; print(x); }; g(1);",
+			errors: []string{"semantic error at 1:25: variable 'x' already declare"},
+		},
+		{
+			name:   "constant redeclared in same scope",
+			input:  "const c = 1; const c = 2;",
+			errors: []string{"semantic error at 1:20: constant 'c' already declared"},
+		},
+		{
+			name:   "constant shadowing outer constant",
+			input:  "const c = 1; fn f() int { const c = c + 1; return c; }\nprint(f());",
+			policy: semantic.ShadowAllow,
+		},
+		{
+			name:     "shadow warning",
+			policy:   semantic.ShadowWarn,
+			input:    "var x = 1; fn f() { if true { var x = 2; print(x); } }\nf(); print(x);",
+			warnings: []string{"semantic warning at 1:35: declaration of 'x' shadows variable declared at 1:5"},
+		},
+		{
+			name:     "parameter shadowing a function",
+			policy:   semantic.ShadowWarn,
+			input:    "fn g() { }\nfn f(g int) int { return g; }\ng(); print(f(1));",
+			warnings: []string{"semantic warning at 2:6: declaration of 'g' shadows function declared at 1:4"},
+		},
+		{
+			name:   "shadow error",
+			policy: semantic.ShadowError,
+			input:  "var x = 1; fn f() { var x = 2; print(x); }\nf(); print(x);",
+			errors: []string{"semantic error at 1:25: declaration of 'x' shadows variable declared at 1:5"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			toks, err := lexer.Tokenize(tt.input)
+			require.NoError(t, err)
+
+			p := parser.New(toks)
+			program := p.ParseProgram()
+			require.Empty(t, p.Errors())
+
+			analyzer := semantic.NewAnalyzer()
+			analyzer.Shadowing = tt.policy
+
+			var errors, warnings []string
+			for _, err := range analyzer.Analyze(program) {
+				errors = append(errors, err.Error())
+			}
+			for _, warning := range analyzer.Warnings {
+				warnings = append(warnings, warning.Error())
+			}
+
+			assert.Equal(t, tt.errors, errors)
+			assert.Equal(t, tt.warnings, warnings)
+		})
+	}
+}
+
+func TestAnalyzer_Structs(t *testing.T) {
+	const point = "type Point struct { x int; y int }\n"
+
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "literal and field access",
+			input: point + "var p = Point{x: 1, y: 2}; var n int = p.x + p.y; p.y = n;",
+		},
+		{
+			name:  "type used before its declaration",
+			input: "fn origin() Point { return Point{}; }\n" + point + "var d = origin().x;",
+		},
+		{
+			name:  "nested struct fields",
+			input: point + "type Line struct { a Point; b Point }\nvar l = Line{a: Point{x: 1}}; l.b.y = l.a.x;",
+		},
+		{
+			name:  "local type",
+			input: "fn f() int { type Pair struct { a int8; b string }; var p = Pair{a: 1}; return int(p.a); }",
+		},
+		{
+			name:  "unknown field in literal",
+			input: point + "var p = Point{z: 1};",
+			want:  []string{"semantic error at 2:15: unknown field 'z' in struct literal of type Point"},
+		},
+		{
+			name:  "duplicate field in literal",
+			input: point + "var p = Point{x: 1, x: 2};",
+			want:  []string{"semantic error at 2:21: duplicate field 'x' in struct literal"},
+		},
+		{
+			name:  "field value of wrong type",
+			input: point + "var p = Point{x: \"one\"};",
+			want:  []string{"semantic error at 2:18: cannot use \"one\" (type string) as int value in struct literal"},
+		},
+		{
+			name:  "field value overflows",
+			input: "type B struct { v uint8 }\nvar b = B{v: 256};",
+			want:  []string{"semantic error at 2:14: cannot use 256 (untyped int constant 256) as uint8 value in struct literal (overflows)"},
+		},
+		{
+			name:  "unknown field access",
+			input: point + "var p = Point{}; print(p.z);",
+			want:  []string{"semantic error at 2:26: p.z undefined (type Point has no field 'z')"},
+		},
+		{
+			name:  "field access on non-struct",
+			input: "var n = 1; print(n.x);",
+			want:  []string{"semantic error at 1:20: n.x undefined (type int has no field 'x')"},
+		},
+		{
+			name:  "assigning a field of the wrong type",
+			input: point + "var p = Point{}; p.x = true;",
+			want:  []string{"semantic error at 2:24: cannot use true (type bool) as int value in assignment"},
+		},
+		{
+			name:  "assigning a field of a call result",
+			input: point + "fn f() Point { return Point{}; } f().x = 1;",
+			want:  []string{"semantic error at 2:34: cannot assign to f().x"},
+		},
+		{
+			name:  "distinct struct types",
+			input: point + "type Vec struct { x int; y int }\nvar v Vec = Point{};",
+			want:  []string{"semantic error at 3:13: cannot use Point{} (type Point) as Vec value in variable declaration"},
+		},
+		{
+			name:  "duplicate field in type",
+			input: "type P struct { x int; x string }",
+			want:  []string{"semantic error at 1:24: field 'x' already declared"},
+		},
+		{
+			name:  "recursive type",
+			input: "type List struct { next List }",
+			want:  []string{"semantic error at 1:6: invalid recursive type 'List'"},
+		},
+		{
+			name:  "undefined type",
+			input: "var p Pointt;",
+			want:  []string{"semantic error at 1:7: undefined type 'Pointt'"},
+		},
+		{
+			name:  "variable used as type",
+			input: "var n = 1; var p = n{};",
+			want:  []string{"semantic error at 1:20: 'n' is not a type"},
+		},
+		{
+			name:  "type used as value",
+			input: point + "var p = Point;",
+			want:  []string{"semantic error at 2:9: type 'Point' is not an expression"},
+		},
+		{
+			name:  "literal of non-struct type",
+			input: "type Celsius float\nvar c = Celsius{};",
+			want:  []string{"semantic error at 2:9: invalid struct literal of non-struct type Celsius"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_Arrays(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "array and slice values",
+			input: "const N = 3; var a [N]int = [1, 2, 3]; var s []int = a[1:]; s[0] = a[2] + len(s); var n int8 = [1, 2][0];",
+		},
+		{
+			name:  "literal without a type is a slice",
+			input: "var s = [1, 2.5]; var f []float = s; var t = s[:1];",
+		},
+		{
+			name:  "slice of a literal",
+			input: "var s = [1, 2][0:1]; var t []int = s; fn f(s []int) { } f([1, 2, 3][1:]);",
+		},
+		{
+			name:  "nested literals",
+			input: "var grid [2][]string = [[\"a\"], []]; print(grid[1][0]);",
+		},
+		{
+			name:  "len of arrays, slices and strings",
+			input: "fn f(xs []bool) int { var a [4]int8; a = [1, 2, 3, 4]; return len(xs) + len(a) + len(\"abc\"); }",
+		},
+		{
+			name:  "len can be redeclared",
+			input: "fn f() { var len = 3; print(len); }",
+		},
+		{
+			name:  "recursive type through a slice",
+			input: "type Tree struct { kids []Tree }\nvar t = Tree{kids: [Tree{}]}; t.kids[0].kids = [];",
+		},
+		{
+			name:  "literal of the wrong length",
+			input: "var a [3]int = [1, 2];",
+			want:  []string{"semantic error at 1:16: cannot use [1, 2] (array literal of length 2) as [3]int value in variable declaration"},
+		},
+		{
+			name:  "element of the wrong type",
+			input: "var a []string = [1];",
+			want:  []string{"semantic error at 1:19: cannot use 1 (type untyped int) as string value in variable declaration"},
+		},
+		{
+			name:  "element overflows",
+			input: "fn f(b []uint8) { } f([1, 256]);",
+			want:  []string{"semantic error at 1:27: cannot use 256 (untyped int constant 256) as uint8 value in argument to 'f' (overflows)"},
+		},
+		{
+			name:  "mismatched elements",
+			input: "var s = [1, \"x\"];",
+			want:  []string{"semantic error at 1:13: mismatched types untyped int and string in array literal"},
+		},
+		{
+			name:  "slice is not an array",
+			input: "var s = [1, 2]; var a [2]int = s;",
+			want:  []string{"semantic error at 1:32: cannot use s (type []int) as [2]int value in variable declaration"},
+		},
+		{
+			name:  "empty literal without a type",
+			input: "var s = [];",
+			want:  []string{"semantic error at 1:9: cannot infer the element type of empty array literal []"},
+		},
+		{
+			name:  "constant index out of bounds",
+			input: "var a [3]int = [1, 2, 3]; print(a[3]);",
+			want:  []string{"semantic error at 1:35: index 3 out of bounds for 3-element array"},
+		},
+		{
+			name:  "negative constant index",
+			input: "const i = -1; var s = [1]; print(s[i]);",
+			want:  []string{"semantic error at 1:36: index -1 must not be negative"},
+		},
+		{
+			name:  "slice bounds",
+			input: "var a [3]int = [1, 2, 3]; var s = a[3:]; var t = a[:4]; var u = a[2:1];",
+			want: []string{
+				"semantic error at 1:53: index 4 out of bounds for 3-element array",
+				"semantic error at 1:65: invalid slice indices: 2 > 1",
+			},
+		},
+		{
+			name:  "non-integer index",
+			input: "var s = [1]; print(s[\"0\"]);",
+			want:  []string{"semantic error at 1:22: index \"0\" (type string) must be integer"},
+		},
+		{
+			name:  "indexing a non-array",
+			input: "var n = 1; print(n[0]); print(n[:]);",
+			want: []string{
+				"semantic error at 1:18: cannot index n (type int)",
+				"semantic error at 1:31: cannot slice n (type int)",
+			},
+		},
+		{
+			name:  "array length not constant",
+			input: "var n = 3; var a [n]int;",
+			want:  []string{"semantic error at 1:19: array length n is not a constant"},
+		},
+		{
+			name:  "negative array length",
+			input: "var a [-1]int;",
+			want:  []string{"semantic error at 1:8: invalid array length (-1)"},
+		},
+		{
+			name:  "len arguments",
+			input: "print(len(1)); print(len());",
+			want: []string{
+				"semantic error at 1:11: invalid argument 1 (type untyped int) for built-in len",
+				"semantic error at 1:22: not enough arguments in call to 'len': have 0, want 1",
+			},
+		},
+		{
+			name:  "len used as a value",
+			input: "var f = len; len = f;",
+			want: []string{
+				"semantic error at 1:9: len (built-in function) must be called",
+				"semantic error at 1:14: cannot assign to built-in function 'len'",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
+
+func TestAnalyzer_Maps(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "map values",
+			input: "var m map[string]int = {\"a\": 1, \"b\": 2}; m[\"c\"] = m[\"a\"] + len(m); var n int8 = {1: 2}[1];",
+		},
+		{
+			name:  "literal without a type",
+			input: "var m = {1: 2.5, 2: 3}; var f map[int]float = m;",
+		},
+		{
+			name:  "comma-ok lookup",
+			input: "fn f(m map[string][]int) bool { var v []int; var ok bool; v, ok = m[\"a\"]; print(v); return ok; }",
+		},
+		{
+			name:  "comparable keys",
+			input: "type P struct { x int; y [2]string }\nvar m map[P]bool = {P{x: 1}: true}; var n map[[2]int]map[string]bool = {[1, 2]: {}};",
+		},
+		{
+			name:  "recursive type through a map",
+			input: "type Node struct { next map[string]Node }\nvar n = Node{next: {\"a\": Node{}}};",
+		},
+		{
+			name:  "key type not comparable",
+			input: "var a map[[]int]int; var b map[fn()]int; var c = {[1]: 2};",
+			want: []string{
+				"semantic error at 1:11: invalid map key type []int",
+				"semantic error at 1:32: invalid map key type fn()",
+				"semantic error at 1:50: invalid map key type []int",
+			},
+		},
+		{
+			name:  "struct key with a slice field",
+			input: "type S struct { xs []int }\nvar m map[S]int;",
+			want:  []string{"semantic error at 2:11: invalid map key type S"},
+		},
+		{
+			name:  "mismatched keys",
+			input: "var m map[string]int = {\"x\": 1, 1: 2};",
+			want:  []string{"semantic error at 1:33: mismatched types string and untyped int in map literal"},
+		},
+		{
+			name:  "pair of the wrong type",
+			input: "var m map[string]int = {\"x\": \"y\"};",
+			want:  []string{"semantic error at 1:30: cannot use \"y\" (type string) as int value in variable declaration"},
+		},
+		{
+			name:  "duplicate constant key",
+			input: "const k = \"a\"; var m = {\"a\": 1, k: 2};",
+			want:  []string{"semantic error at 1:33: duplicate key k in map literal"},
+		},
+		{
+			name:  "empty literal without a type",
+			input: "var m = {};",
+			want:  []string{"semantic error at 1:9: cannot infer the key and value types of empty map literal {}"},
+		},
+		{
+			name:  "key of the wrong type",
+			input: "var m = {\"a\": 1}; print(m[1]); m[true] = 2;",
+			want: []string{
+				"semantic error at 1:27: cannot use 1 (type untyped int) as string value in map index",
+				"semantic error at 1:34: cannot use true (type bool) as string value in map index",
+			},
+		},
+		{
+			name:  "assignment mismatch",
+			input: "var m = {\"a\": 1}; var x int; var ok bool; x, ok, ok = m[\"a\"]; x, ok = len(m);",
+			want: []string{
+				"semantic error at 1:43: assignment mismatch: 3 variables but 1 value",
+				"semantic error at 1:63: assignment mismatch: 2 variables but 1 value",
+			},
+		},
+		{
+			name:  "comma-ok into the wrong types",
+			input: "var m = {\"a\": 1}; var s string; var n int; s, n = m[\"a\"];",
+			want: []string{
+				"semantic error at 1:51: cannot use m[\"a\"] (type int) as string value in assignment",
+				"semantic error at 1:51: cannot use m[\"a\"] (type bool) as int value in assignment",
+			},
+		},
+		{
+			name:  "maps are not comparable",
+			input: "var m = {\"a\": 1}; print(m == m);",
+			want:  []string{"semantic error at 1:25: operator '==' is not defined on map[string]int"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyze(t, tt.input))
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package semantic
+
+import (
+	"ixion/internal/ast"
+	"ixion/internal/types"
+)
+
+// declareType declares the type named by td in the current scope. Its
+// underlying type is resolved later by completeType, so that declarations
+// may refer to types declared after them.
+func (a *Analyzer) declareType(td *ast.TypeDeclaration) *types.Named {
+	named := types.NewNamed(td.Name.Value, nil)
+
+	if a.declare(td.Name, named, TypeSymbol) == nil {
+		a.errf(td.Name, "type '%s' already declared", td.Name.Value)
+		return nil
+	}
+
+	a.pendingTypes[named] = td
+	return named
+}
+
+// completeType resolves the underlying type of a declared type, unless it
+// is already resolved. A type that contains itself is reported, since a
+// value of it would have infinite size.
+func (a *Analyzer) completeType(named *types.Named) {
+	td, ok := a.pendingTypes[named]
+	if !ok {
+		return
+	}
+
+	if a.resolvingTypes[named] {
+		a.errf(td.Name, "invalid recursive type '%s'", td.Name.Value)
+		delete(a.pendingTypes, named)
+		named.SetUnderlying(invalidType)
+		return
+	}
+
+	a.resolvingTypes[named] = true
+	underlying := a.typeOf(td.Type)
+	delete(a.resolvingTypes, named)
+
+	// The declaration may have been found recursive meanwhile.
+	if _, ok := a.pendingTypes[named]; ok {
+		delete(a.pendingTypes, named)
+		named.SetUnderlying(underlying)
+	}
+}
+
+// visitTypeDecl declares a type inside a function body. Unlike top-level
+// types, it is only visible after its declaration.
+func (a *Analyzer) visitTypeDecl(td *ast.TypeDeclaration) {
+	if named := a.declareType(td); named != nil {
+		a.completeType(named)
+	}
+}
+
+// lookupType resolves the name of a declared type.
+func (a *Analyzer) lookupType(ident *ast.Identifier) types.Type {
+	symbol := a.resolve(ident.Value)
+	if symbol == nil {
+		a.errf(ident, "undefined type '%s'", ident.Value)
+		return invalidType
+	}
+	if symbol.Kind != TypeSymbol {
+		a.errf(ident, "'%s' is not a type", ident.Value)
+		return invalidType
+	}
+
+	// Behind an indirection the type may still be incomplete; it is
+	// completed with the other declarations.
+	named := symbol.Type.(*types.Named)
+	if a.indirect == 0 {
+		a.completeType(named)
+	}
+	return named
+}
+
+// structType resolves a struct type annotation.
+func (a *Analyzer) structType(st *ast.StructType) *types.Struct {
+	fields := make([]*types.Field, 0, len(st.Fields))
+	seen := make(map[string]bool)
+
+	for _, f := range st.Fields {
+		if seen[f.Name.Value] {
+			a.errf(f.Name, "field '%s' already declared", f.Name.Value)
+			continue
+		}
+		seen[f.Name.Value] = true
+
+		fields = append(fields, &types.Field{Name: f.Name.Value, Type: a.typeOf(f.Type)})
+	}
+
+	return types.NewStruct(fields)
+}
+
+// visitStructLiteral checks the fields of a struct literal against its
+// type. Fields that are left out are zero.
+func (a *Analyzer) visitStructLiteral(sl *ast.StructLiteral) types.Type {
+	t := invalidType
+	if ident, ok := sl.Type.(*ast.Identifier); ok {
+		t = a.lookupType(ident)
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok && !types.IsInvalid(t) {
+		a.errf(sl.Type, "invalid struct literal of non-struct type %s", t)
+		t = invalidType
+	}
+
+	seen := make(map[string]bool)
+	for _, fv := range sl.Fields {
+		valueType := a.visitValue(fv.Value)
+		if st == nil {
+			continue
+		}
+
+		field := st.Field(fv.Name.Value)
+		switch {
+		case field == nil:
+			a.errf(fv.Name, "unknown field '%s' in struct literal of type %s", fv.Name.Value, t)
+		case seen[field.Name]:
+			a.errf(fv.Name, "duplicate field '%s' in struct literal", fv.Name.Value)
+		default:
+			a.checkAssignable(fv.Value, valueType, field.Type, "struct literal")
+		}
+		seen[fv.Name.Value] = true
+	}
+
+	return t
+}
+
+// visitSelectorExpression checks an access to a field of a struct value
+// and returns the type of the field.
+func (a *Analyzer) visitSelectorExpression(se *ast.SelectorExpression) types.Type {
+	t := a.visitValue(se.Left)
+	if types.IsInvalid(t) {
+		return invalidType
+	}
+
+	if st, ok := t.Underlying().(*types.Struct); ok {
+		if field := st.Field(se.Field.Value); field != nil {
+			return field.Type
+		}
+	}
+
+	a.errf(se.Field, "%s undefined (type %s has no field '%s')", se, t, se.Field.Value)
+	return invalidType
+}
+
+// visitFieldTarget checks a field access on the left side of an
+// assignment. Only a field of a variable or parameter, or a field of one
+// of its fields or elements, can be assigned.
+func (a *Analyzer) visitFieldTarget(se *ast.SelectorExpression) types.Type {
+	root := se.Left
+	for {
+		if inner, ok := root.(*ast.SelectorExpression); ok {
+			root = inner.Left
+		} else if inner, ok := root.(*ast.IndexExpression); ok {
+			root = inner.Left
+		} else {
+			break
+		}
+	}
+
+	if ident, ok := root.(*ast.Identifier); ok {
+		if symbol := a.resolve(ident.Value); symbol != nil &&
+			symbol.Kind != VariableSymbol && symbol.Kind != ParameterSymbol {
+			a.errf(se, "cannot assign to %s: '%s' is a %s", se, ident.Value, symbol.Kind)
+			return invalidType
+		}
+	} else {
+		a.errf(se, "cannot assign to %s", se)
+		return invalidType
+	}
+
+	return a.visitSelectorExpression(se)
+}
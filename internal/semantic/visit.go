@@ -1,20 +1,48 @@
 package semantic
 
 import (
+	"fmt"
+	"math"
+	"math/big"
+
 	"ixion/internal/ast"
+	"ixion/internal/token"
+	"ixion/internal/types"
 )
 
-const (
-	unknownType = "unknown type"
-	funcType    = "function"
+// visitProgram declares every top-level type and then every top-level
+// function before checking any statement, so that types and functions can
+// be used before their declaration and can refer to each other.
+// Everything else is checked in source order.
+func (a *Analyzer) visitProgram(program *ast.Program) {
+	var named []*types.Named
+	for _, stmt := range program.Statements {
+		if td, ok := stmt.(*ast.TypeDeclaration); ok {
+			if t := a.declareType(td); t != nil {
+				named = append(named, t)
+			}
+		}
+	}
+	for _, t := range named {
+		a.completeType(t)
+	}
 
-	intType    = "int"
-	stringType = "string"
-)
+	signatures := make(map[*ast.FunctionDeclaration]*types.Func)
+	for _, stmt := range program.Statements {
+		if fd, ok := stmt.(*ast.FunctionDeclaration); ok {
+			signatures[fd] = a.declareFunc(fd)
+		}
+	}
 
-func (a *Analyzer) visitProgram(program *ast.Program) {
 	for _, stmt := range program.Statements {
-		a.visitStmt(stmt)
+		switch s := stmt.(type) {
+		case *ast.TypeDeclaration:
+			// Already declared.
+		case *ast.FunctionDeclaration:
+			a.visitFuncBody(s, signatures[s])
+		default:
+			a.visitStmt(stmt)
+		}
 	}
 }
 
@@ -22,6 +50,8 @@ func (a *Analyzer) visitStmt(stmt ast.Statement) {
 	switch x := stmt.(type) {
 	case *ast.VarStatement:
 		a.visitVarStmt(x)
+	case *ast.ConstStatement:
+		a.visitConstStmt(x)
 	case *ast.ExpressionStatement:
 		a.visitExpressionStmt(x)
 	case *ast.ReturnStatement:
@@ -30,31 +60,114 @@ func (a *Analyzer) visitStmt(stmt ast.Statement) {
 		a.vistPrintStmt(x)
 	case *ast.FunctionDeclaration:
 		a.visitFuncDecl(x)
+	case *ast.TypeDeclaration:
+		a.visitTypeDecl(x)
 	case *ast.BlockStatement:
 		a.visitBlockStmt(x)
+	case *ast.IfStatement:
+		a.visitIfStmt(x)
+	case *ast.ForStatement:
+		a.visitForStmt(x, &loop{})
+	case *ast.BreakStatement:
+		a.visitBranchStmt(x, x.Label, "break")
+	case *ast.ContinueStatement:
+		a.visitBranchStmt(x, x.Label, "continue")
+	case *ast.LabeledStatement:
+		a.visitLabeledStmt(x)
 	}
 }
 
 func (a *Analyzer) visitVarStmt(vs *ast.VarStatement) {
-	if symbol := a.resolve(vs.Name.Value); symbol != nil {
-		a.errf(vs, "variable '%s' already declare", vs.Name.Value)
+	varType := invalidType
+	if vs.Type != nil {
+		varType = a.typeOf(vs.Type)
+	}
+
+	symbol := a.declare(vs.Name, varType, VariableSymbol)
+	if symbol == nil {
+		a.errf(vs.Name, "variable '%s' already declare", vs.Name.Value)
+	}
+
+	if vs.Value == nil {
+		if symbol != nil {
+			a.unassigned[symbol] = true
+		}
+		return
+	}
+
+	// The variable is in scope, but has no value, in its own initializer.
+	if symbol != nil {
+		symbol.initializing = true
+	}
+	valueType := a.visitValue(vs.Value)
+	if symbol != nil {
+		symbol.initializing = false
 	}
 
-	var varType string
 	if vs.Type != nil {
-		varType = vs.Type.String()
-	} else if vs.Value != nil {
-		varType = a.getExprType(vs.Value)
-	} else {
-		varType = unknownType
+		a.checkAssignable(vs.Value, valueType, varType, "variable declaration")
+		return
 	}
 
-	if !a.declare(vs.Name.Value, varType) {
-		a.errf(vs, "variable '%s' already declare in these scope", vs.Name.Value)
+	switch lit := vs.Value.(type) {
+	case *ast.ArrayLiteral:
+		if len(lit.Elements) == 0 {
+			a.errf(lit, "cannot infer the element type of empty array literal %s", lit)
+		}
+	case *ast.MapLiteral:
+		if len(lit.Pairs) == 0 {
+			a.errf(lit, "cannot infer the key and value types of empty map literal %s", lit)
+		} else if m, ok := valueType.(*types.Map); ok && !types.Comparable(m.Key) {
+			a.errf(lit, "invalid map key type %s", types.Default(m.Key))
+		}
 	}
 
-	if vs.Value != nil {
-		a.visitExpression(vs.Value)
+	// An untyped constant must still fit the type it defaults to.
+	a.checkAssignable(vs.Value, valueType, types.Default(valueType), "variable declaration")
+	if symbol != nil {
+		symbol.Type = types.Default(valueType)
+	}
+}
+
+// visitConstStmt evaluates the initializer of a constant at compile time
+// and records its value on the symbol.
+func (a *Analyzer) visitConstStmt(cs *ast.ConstStatement) {
+	if cs.Name == nil || cs.Value == nil {
+		return
+	}
+
+	if a.CurrentScope.exist(cs.Name.Value) {
+		a.errf(cs.Name, "constant '%s' already declared", cs.Name.Value)
+		return
+	}
+
+	errCount := len(a.Errors)
+	constType := a.visitValue(cs.Value)
+
+	value, ok := a.evalConst(cs.Value)
+	if !ok {
+		// Do not pile a second error on an initializer that already failed.
+		if len(a.Errors) == errCount {
+			a.errf(cs.Value, "const initializer %s is not a constant expression", cs.Value.String())
+		}
+		constType = invalidType
+	}
+
+	if cs.Type != nil {
+		declared := a.typeOf(cs.Type)
+		if ok && !types.AssignableTo(constType, declared) {
+			a.errf(cs.Value, "cannot use %s (%s constant) as %s value in constant declaration",
+				cs.Value.String(), constType, declared)
+		} else if v, fits := a.fitsConst(cs.Value, declared); types.IsUntyped(constType) && !fits {
+			a.errf(cs.Value, "cannot use %s (%s constant %s) as %s value in constant declaration (overflows)",
+				cs.Value.String(), constType, v, declared)
+		}
+		constType = declared
+	}
+
+	symbol := a.declare(cs.Name, constType, ConstantSymbol)
+	if ok {
+		symbol.Value = value
 	}
 }
 
@@ -64,36 +177,84 @@ func (a *Analyzer) visitExpressionStmt(es *ast.ExpressionStatement) {
 
 func (a *Analyzer) vistPrintStmt(ps *ast.PrintStatement) {
 	if ps.Value != nil {
-		a.visitExpression(ps.Value)
+		a.visitValue(ps.Value)
 	}
 }
 
+// visitReturnStmt checks a returned value against the return type of the
+// enclosing function.
 func (a *Analyzer) visitReturnStmt(rs *ast.ReturnStatement) {
+	valueType := invalidType
 	if rs.ReturnValue != nil {
-		a.visitExpression(rs.ReturnValue)
+		valueType = a.visitValue(rs.ReturnValue)
+	}
+
+	fn := a.function
+	switch {
+	case fn == nil:
+		a.err(rs, "return statement outside function")
+	case fn.returnType == nil && rs.ReturnValue != nil:
+		a.errf(rs.ReturnValue, "unexpected return value: %s has no return type", fn)
+	case fn.returnType != nil && rs.ReturnValue == nil:
+		a.errf(rs, "missing return value: %s returns %s", fn, fn.returnType)
+	case rs.ReturnValue != nil:
+		a.checkAssignable(rs.ReturnValue, valueType, fn.returnType, "return statement")
 	}
 }
 
 func (a *Analyzer) visitFuncDecl(fd *ast.FunctionDeclaration) {
-	if !a.declare(fd.Name.Value, funcType) {
-		a.errf(fd, "function '%s' already declare", fd.Name.Value)
+	a.visitFuncBody(fd, a.declareFunc(fd))
+}
+
+// declareFunc declares the function fd in the current scope and returns
+// its signature.
+func (a *Analyzer) declareFunc(fd *ast.FunctionDeclaration) *types.Func {
+	signature := a.funcType(fd.Parameters, fd.ReturnType)
+
+	if a.declare(fd.Name, signature, FunctionSymbol) == nil {
+		a.errf(fd.Name, "function '%s' already declare", fd.Name.Value)
 	}
 
-	a.enterScope()
+	return signature
+}
 
-	for _, param := range fd.Parameters {
-		paramType := unknownType
-		if param.Type != nil {
-			paramType = param.Type.String()
-		}
-		if !a.declare(param.Name.Value, paramType) {
-			a.errf(param, "parameter '%s' already declared", param.Name.Value)
+func (a *Analyzer) visitFuncBody(fd *ast.FunctionDeclaration, signature *types.Func) {
+	defer a.enterFunction(&function{name: fd.Name.Value, returnType: signature.Result})()
+
+	a.declareParams(fd.Parameters, signature)
+	a.visitBody(fd.Body)
+	a.checkMissingReturn(fd.Body, signature.Result)
+}
+
+// visitFunctionLiteral analyzes the body of an anonymous function in its
+// own scope and returns the type of the function.
+func (a *Analyzer) visitFunctionLiteral(fl *ast.FunctionLiteral) *types.Func {
+	signature := a.funcType(fl.Parameters, fl.ReturnType)
+
+	defer a.enterFunction(&function{returnType: signature.Result, literal: fl})()
+
+	a.declareParams(fl.Parameters, signature)
+	a.visitBody(fl.Body)
+	a.checkMissingReturn(fl.Body, signature.Result)
+
+	return signature
+}
+
+func (a *Analyzer) declareParams(params []*ast.FunctionParameter, signature *types.Func) {
+	for i, param := range params {
+		if a.declare(param.Name, signature.Params[i], ParameterSymbol) == nil {
+			a.errf(param.Name, "parameter '%s' already declared", param.Name.Value)
 		}
 	}
+}
 
-	a.visitBlockStmt(fd.Body)
-
-	a.exitScope()
+// visitBody analyzes the body of a function in the scope opened by
+// enterFunction, so that a declaration at the top level of the body
+// cannot redeclare a parameter.
+func (a *Analyzer) visitBody(body *ast.BlockStatement) {
+	for _, stmt := range body.Statements {
+		a.visitStmt(stmt)
+	}
 }
 
 func (a *Analyzer) visitBlockStmt(bs *ast.BlockStatement) {
@@ -104,81 +265,566 @@ func (a *Analyzer) visitBlockStmt(bs *ast.BlockStatement) {
 	a.exitScope()
 }
 
-func (a *Analyzer) visitExpression(expr ast.Expression) {
+// visitIfStmt checks the condition of every branch in an if / else if /
+// else chain. Each branch body is analyzed in its own scope.
+func (a *Analyzer) visitIfStmt(is *ast.IfStatement) {
+	a.checkCondition(is.Condition)
+
+	before := a.unassigned
+	consequence, consFalls := a.branchState(is.Consequence, before)
+	alternative, altFalls := a.branchState(is.Alternative, before)
+
+	// A variable is assigned after the if statement if every branch that
+	// falls through assigns it.
+	switch {
+	case consFalls && altFalls:
+		a.unassigned = consequence.join(alternative)
+	case consFalls:
+		a.unassigned = consequence
+	case altFalls:
+		a.unassigned = alternative
+	default:
+		a.unassigned = assignState{}
+	}
+}
+
+// visitForStmt analyzes a loop in its own scope, so that variables
+// declared in the init statement are only visible inside the loop. l
+// collects the breaks out of the loop.
+func (a *Analyzer) visitForStmt(fs *ast.ForStatement, l *loop) {
+	a.enterScope()
+	defer a.exitScope()
+
+	a.loops = append(a.loops, l)
+	defer func() { a.loops = a.loops[:len(a.loops)-1] }()
+
+	if fs.Init != nil {
+		a.visitStmt(fs.Init)
+	}
+
+	if fs.Condition != nil {
+		a.checkCondition(fs.Condition)
+	}
+
+	before := a.unassigned.clone()
+
+	a.visitBlockStmt(fs.Body)
+
+	if fs.Post != nil {
+		a.visitStmt(fs.Post)
+	}
+
+	// With a condition, the body may not run at all, so assignments in it
+	// and in the post statement do not count after the loop.
+	if fs.Condition != nil {
+		a.unassigned = before
+		return
+	}
+
+	// Without one, the body runs at least once and the loop is only left
+	// by a break. A variable is assigned after the loop if every break
+	// assigns it; with no break, the code after the loop is unreachable.
+	a.unassigned = assignState{}
+	for i, state := range l.breaks {
+		if i == 0 {
+			a.unassigned = state
+		} else {
+			a.unassigned = a.unassigned.join(state)
+		}
+	}
+}
+
+// visitBranchStmt checks that a break or continue statement is inside a
+// loop and that its label, if any, names an enclosing loop.
+func (a *Analyzer) visitBranchStmt(stmt ast.Statement, labelIdent *ast.Identifier, keyword string) {
+	var target *loop
+
+	if labelIdent == nil {
+		if len(a.loops) == 0 {
+			a.errf(stmt, "%s is not in a loop", keyword)
+			return
+		}
+		target = a.loops[len(a.loops)-1]
+	} else {
+		l := a.lookupLabel(labelIdent.Value)
+		if l == nil {
+			a.errf(labelIdent, "%s label not defined: '%s'", keyword, labelIdent.Value)
+			return
+		}
+		if l.loop == nil {
+			a.errf(labelIdent, "invalid %s label '%s': not a loop", keyword, labelIdent.Value)
+			return
+		}
+		target = l.loop
+	}
+
+	if keyword == "break" {
+		target.breaks = append(target.breaks, a.unassigned.clone())
+	}
+}
+
+func (a *Analyzer) visitLabeledStmt(ls *ast.LabeledStatement) {
+	if a.lookupLabel(ls.Label.Value) != nil {
+		a.errf(ls.Label, "label '%s' already defined", ls.Label.Value)
+	}
+
+	l := &label{name: ls.Label.Value}
+	a.labels = append(a.labels, l)
+
+	if fs, ok := ls.Statement.(*ast.ForStatement); ok {
+		l.loop = &loop{}
+		a.visitForStmt(fs, l.loop)
+	} else {
+		a.visitStmt(ls.Statement)
+	}
+
+	a.labels = a.labels[:len(a.labels)-1]
+}
+
+// checkCondition reports an error if a branch or loop condition is known
+// not to be a bool.
+func (a *Analyzer) checkCondition(cond ast.Expression) {
+	if cond == nil {
+		return
+	}
+
+	if t := a.visitValue(cond); !types.IsInvalid(t) && !types.IsBoolean(t) {
+		a.errf(cond, "non-bool %s used as condition", t)
+	}
+}
+
+// visitValue visits an expression whose result is used as a value and
+// returns its type. Calls to functions without a return type are
+// rejected here.
+func (a *Analyzer) visitValue(expr ast.Expression) types.Type {
+	t := a.visitExpression(expr)
+	if t == voidType {
+		a.errf(expr, "%s (no value) used as value", expr.String())
+		return invalidType
+	}
+	return t
+}
+
+// checkAssignable reports an error if a value of type valueType cannot be
+// used where a value of type target is expected.
+func (a *Analyzer) checkAssignable(value ast.Expression, valueType, target types.Type, context string) {
+	if al, ok := value.(*ast.ArrayLiteral); ok && a.checkArrayLiteral(al, valueType, target, context) {
+		return
+	}
+	if ml, ok := value.(*ast.MapLiteral); ok && a.checkMapLiteral(ml, valueType, target, context) {
+		return
+	}
+
+	if !types.AssignableTo(valueType, target) {
+		a.errf(value, "cannot use %s (type %s) as %s value in %s", value.String(), valueType, target, context)
+		return
+	}
+
+	// Typed constants were range checked when they got their type.
+	if !types.IsUntyped(valueType) {
+		return
+	}
+	if v, ok := a.fitsConst(value, target); !ok {
+		a.errf(value, "cannot use %s (%s constant %s) as %s value in %s (overflows)",
+			value.String(), valueType, v, target, context)
+	}
+}
+
+// fitsConst reports whether expr, if it is an integer constant, is
+// representable by type target. The constant value is returned as well.
+func (a *Analyzer) fitsConst(expr ast.Expression, target types.Type) (*big.Int, bool) {
+	value, ok := a.evalConst(expr)
+	if !ok {
+		return nil, true
+	}
+
+	v, isInt := value.(*big.Int)
+	if !isInt {
+		return nil, true
+	}
+	return v, types.Representable(v, target)
+}
+
+// checkOverflow reports an error if expr is a constant of the typed
+// integer type t whose value does not fit t.
+func (a *Analyzer) checkOverflow(expr ast.Expression, t types.Type) types.Type {
+	if types.IsUntyped(t) || !types.IsInteger(t) {
+		return t
+	}
+
+	if v, ok := a.fitsConst(expr, t); !ok {
+		a.errf(expr, "constant %s overflows %s", v, t)
+	}
+	return t
+}
+
+// visitExpression checks an expression and returns its type, or
+// invalidType if the type cannot be determined. Errors are reported once,
+// where they occur; an unknown operand type silences further checks.
+func (a *Analyzer) visitExpression(expr ast.Expression) types.Type {
 	switch e := expr.(type) {
 	case *ast.Identifier:
-		a.visitIdentifier(e)
+		return a.visitIdentifier(e)
 	case *ast.IntegerLiteral:
-		// Ничего не проверяем для литералов
+		return untypedIntType
+	case *ast.FloatLiteral:
+		return untypedFloatType
 	case *ast.StringLiteral:
-		// Ничего не проверяем для литералов
+		return stringType
+	case *ast.BooleanLiteral:
+		return boolType
 	case *ast.PrefixExpression:
-		a.visitPrefixExpression(e)
+		return a.visitPrefixExpression(e)
 	case *ast.InfixExpression:
-		a.visitInfixExpression(e)
+		return a.visitInfixExpression(e)
 	case *ast.AssignmentExpression:
-		a.visitAssignmentExpression(e)
+		return a.visitAssignmentExpression(e)
 	case *ast.CallExpression:
-		a.visitCallExpression(e)
+		return a.visitCallExpression(e)
+	case *ast.ConversionExpression:
+		return a.visitConversionExpression(e)
+	case *ast.FunctionLiteral:
+		return a.visitFunctionLiteral(e)
+	case *ast.StructLiteral:
+		return a.visitStructLiteral(e)
+	case *ast.SelectorExpression:
+		return a.visitSelectorExpression(e)
+	case *ast.ArrayLiteral:
+		return a.visitArrayLiteral(e)
+	case *ast.IndexExpression:
+		return a.visitIndexExpression(e)
+	case *ast.SliceExpression:
+		return a.visitSliceExpression(e)
+	case *ast.MapLiteral:
+		return a.visitMapLiteral(e)
+	case *ast.MultiAssignmentExpression:
+		return a.visitMultiAssignment(e)
+	default:
+		return invalidType
 	}
 }
 
-func (a *Analyzer) visitIdentifier(id *ast.Identifier) {
+func (a *Analyzer) visitIdentifier(id *ast.Identifier) types.Type {
 	// Проверяем, объявлена ли переменная
-	if symbol := a.resolve(id.Value); symbol == nil {
+	symbol := a.resolve(id.Value)
+	if symbol == nil {
 		a.errf(id, "undeclared variable '%s'", id.Value)
+		return invalidType
+	}
+	switch symbol.Kind {
+	case TypeSymbol:
+		a.errf(id, "type '%s' is not an expression", id.Value)
+		return invalidType
+	case BuiltinSymbol:
+		a.errf(id, "%s (built-in function) must be called", id.Value)
+		return invalidType
 	}
-}
 
-func (a *Analyzer) visitPrefixExpression(pe *ast.PrefixExpression) {
-	a.visitExpression(pe.Right)
-	// TODO: Check compatibility of operand type with operator
+	a.use(id, symbol)
+	return symbol.Type
 }
 
-func (a *Analyzer) visitInfixExpression(ie *ast.InfixExpression) {
-	a.visitExpression(ie.Left)
-	a.visitExpression(ie.Right)
-	// TODO: Check compatibility of operand types
+func (a *Analyzer) visitPrefixExpression(pe *ast.PrefixExpression) types.Type {
+	operandType := a.visitValue(pe.Right)
+
+	switch pe.Operator {
+	case "!":
+		a.expectBool(pe.Right, operandType, pe.Operator)
+		return boolType
+	case "-":
+		if !types.IsInvalid(operandType) && !types.IsNumeric(operandType) {
+			a.errf(pe, "operator '%s' is not defined on %s", pe.Operator, operandType)
+			return invalidType
+		}
+		return a.checkOverflow(pe, operandType)
+	default:
+		return invalidType
+	}
 }
 
-func (a *Analyzer) visitAssignmentExpression(ae *ast.AssignmentExpression) {
-	if ident, ok := ae.Left.(*ast.Identifier); ok {
-		if symbol := a.resolve(ident.Value); symbol == nil {
-			a.errf(ae, "cannot assign to undeclared variable '%s'", ident.Value)
+func (a *Analyzer) visitInfixExpression(ie *ast.InfixExpression) types.Type {
+	leftType := a.visitValue(ie.Left)
+	rightType := a.visitValue(ie.Right)
+
+	if ordered, ok := comparisonOperators[ie.Operator]; ok {
+		a.checkComparison(ie, ordered, leftType, rightType)
+		return boolType
+	}
+
+	if logicalOperators[ie.Operator] {
+		a.expectBool(ie.Left, leftType, ie.Operator)
+		a.expectBool(ie.Right, rightType, ie.Operator)
+		return boolType
+	}
+
+	if arithmeticOperators[ie.Operator] {
+		t := a.checkArithmetic(ie, leftType, rightType)
+		// Evaluate a constant expression here, whatever it is used as, so
+		// that errors such as a division by zero are always reported.
+		if !types.IsInvalid(t) {
+			a.evalConst(ie)
 		}
-	} else {
-		a.err(ae, "left side of assignment must be an identifier")
+		return a.checkOverflow(ie, t)
+	}
+
+	return invalidType
+}
+
+// expectBool reports an error if the operand of a boolean operator is
+// known not to be a bool.
+func (a *Analyzer) expectBool(operand ast.Expression, operandType types.Type, operator string) {
+	if !types.IsInvalid(operandType) && !types.IsBoolean(operandType) {
+		a.errf(operand, "operator '%s' requires bool operand, got %s", operator, operandType)
+	}
+}
+
+func (a *Analyzer) checkComparison(ie *ast.InfixExpression, ordered bool, leftType, rightType types.Type) {
+	if types.IsInvalid(leftType) || types.IsInvalid(rightType) {
+		return
+	}
+
+	t, ok := operandType(leftType, rightType)
+	if !ok {
+		a.errf(ie, "mismatched types %s and %s in comparison '%s'", leftType, rightType, ie.Operator)
+		return
+	}
+	if !a.checkOperands(ie, t, leftType, rightType) {
+		return
+	}
+
+	if (ordered && !types.IsOrdered(t)) || !types.Comparable(t) {
+		a.errf(ie, "operator '%s' is not defined on %s", ie.Operator, t)
+	}
+}
+
+// checkArithmetic checks the operands of + - * / and returns the type of
+// the result.
+func (a *Analyzer) checkArithmetic(ie *ast.InfixExpression, leftType, rightType types.Type) types.Type {
+	if types.IsInvalid(leftType) || types.IsInvalid(rightType) {
+		return invalidType
+	}
+
+	t, ok := operandType(leftType, rightType)
+	if !ok {
+		a.errf(ie, "mismatched types %s and %s in operation '%s'", leftType, rightType, ie.Operator)
+		return invalidType
+	}
+	if !a.checkOperands(ie, t, leftType, rightType) {
+		return invalidType
+	}
+
+	if !types.IsNumeric(t) && !(types.IsString(t) && ie.Operator == "+") {
+		a.errf(ie, "operator '%s' is not defined on %s", ie.Operator, t)
+		return invalidType
 	}
 
-	a.visitExpression(ae.Value)
+	return t
 }
 
-func (a *Analyzer) visitCallExpression(ce *ast.CallExpression) {
-	if ident, ok := ce.Function.(*ast.Identifier); ok {
-		if symbol := a.resolve(ident.Value); symbol == nil {
-			a.errf(ce, "call to undeclared function '%s'", ident.Value)
-		} else if symbol.Type != funcType {
-			a.errf(ce, "'%s' is not a function", ident.Value)
+// checkOperands reports an untyped constant operand of ie that does not
+// fit the type t it is converted to.
+func (a *Analyzer) checkOperands(ie *ast.InfixExpression, t, leftType, rightType types.Type) bool {
+	ok := true
+	for _, operand := range []struct {
+		expr ast.Expression
+		typ  types.Type
+	}{{ie.Left, leftType}, {ie.Right, rightType}} {
+		if !types.IsUntyped(operand.typ) {
+			continue
+		}
+		if v, fits := a.fitsConst(operand.expr, t); !fits {
+			a.errf(operand.expr, "%s (%s constant %s) overflows %s", operand.expr.String(), operand.typ, v, t)
+			ok = false
 		}
 	}
+	return ok
+}
+
+func (a *Analyzer) visitAssignmentExpression(ae *ast.AssignmentExpression) types.Type {
+	targetType, target := a.visitAssignTarget(ae.Left)
 
-	for _, arg := range ce.Arguments {
-		a.visitExpression(arg)
+	valueType := a.visitValue(ae.Value)
+	a.checkAssignable(ae.Value, valueType, targetType, "assignment")
+
+	if target != nil {
+		delete(a.unassigned, target)
 	}
+
+	return targetType
 }
 
-func (a *Analyzer) getExprType(expr ast.Expression) string {
-	switch x := expr.(type) {
-	case *ast.IntegerLiteral:
-		return intType
-	case *ast.StringLiteral:
-		return stringType
+// visitAssignTarget checks the left side of an assignment and returns its
+// type, and the variable it assigns if it is a whole variable.
+func (a *Analyzer) visitAssignTarget(left ast.Expression) (types.Type, *Symbol) {
+	targetType := invalidType
+	var target *Symbol
+
+	switch left := left.(type) {
+	case *ast.Identifier:
+		if symbol := a.resolve(left.Value); symbol == nil {
+			a.errf(left, "cannot assign to undeclared variable '%s'", left.Value)
+		} else if symbol.Kind == ConstantSymbol || symbol.Kind == TypeSymbol || symbol.Kind == BuiltinSymbol {
+			a.errf(left, "cannot assign to %s '%s'", symbol.Kind, left.Value)
+		} else {
+			a.capture(symbol)
+			target, targetType = symbol, symbol.Type
+		}
+	case *ast.SelectorExpression:
+		targetType = a.visitFieldTarget(left)
+	case *ast.IndexExpression:
+		targetType = a.visitIndexExpression(left)
+	default:
+		a.err(left, "left side of assignment must be an identifier, a field or an element")
+	}
+
+	return targetType, target
+}
+
+func (a *Analyzer) visitCallExpression(ce *ast.CallExpression) types.Type {
+	var signature *types.Func
+	name := ce.Function.String()
+
+	switch fn := ce.Function.(type) {
 	case *ast.Identifier:
-		if symbol := a.resolve(x.Value); symbol != nil {
-			return symbol.Type
+		symbol := a.resolve(fn.Value)
+		if symbol == nil {
+			a.errf(fn, "call to undeclared function '%s'", fn.Value)
+			break
 		}
-		return unknownType
+		if symbol.Kind == BuiltinSymbol {
+			return a.visitBuiltinCall(ce, symbol)
+		}
+		a.use(fn, symbol)
+		if f, ok := symbol.Type.Underlying().(*types.Func); ok {
+			signature = f
+		} else if !types.IsInvalid(symbol.Type) {
+			a.errf(fn, "'%s' is not a function", fn.Value)
+		}
+	case *ast.FunctionLiteral:
+		signature = a.visitFunctionLiteral(fn)
+		name = "function literal"
 	default:
-		return unknownType
+		t := a.visitValue(ce.Function)
+		if f, ok := t.Underlying().(*types.Func); ok {
+			signature = f
+		} else if !types.IsInvalid(t) {
+			a.errf(ce.Function, "cannot call non-function %s (type %s)", ce.Function.String(), t)
+		}
+	}
+
+	argTypes := make([]types.Type, len(ce.Arguments))
+	for i, arg := range ce.Arguments {
+		argTypes[i] = a.visitValue(arg)
+	}
+
+	// Without a signature, because the callee has an error, only the
+	// arguments themselves can be checked.
+	if signature == nil {
+		return invalidType
+	}
+
+	resultType := signature.Result
+	if resultType == nil {
+		resultType = voidType
+	}
+
+	if !a.checkArgCount(ce, name, len(signature.Params)) {
+		return resultType
+	}
+
+	for i, arg := range ce.Arguments {
+		a.checkAssignable(arg, argTypes[i], signature.Params[i], fmt.Sprintf("argument to '%s'", name))
+	}
+
+	return resultType
+}
+
+// checkArgCount reports a call to the function name that does not pass
+// exactly want arguments.
+func (a *Analyzer) checkArgCount(ce *ast.CallExpression, name string, want int) bool {
+	if len(ce.Arguments) == want {
+		return true
+	}
+
+	problem := "not enough"
+	if len(ce.Arguments) > want {
+		problem = "too many"
+	}
+	a.errf(ce, "%s arguments in call to '%s': have %d, want %d", problem, name, len(ce.Arguments), want)
+	return false
+}
+
+// visitConversionExpression checks an explicit conversion such as
+// int8(x) and returns the target type.
+func (a *Analyzer) visitConversionExpression(ce *ast.ConversionExpression) types.Type {
+	valueType := a.visitValue(ce.Value)
+	target := a.typeOf(ce.Type)
+
+	if !types.ConvertibleTo(valueType, target) {
+		a.errf(ce, "cannot convert %s (type %s) to type %s", ce.Value.String(), valueType, target)
+		return target
+	}
+
+	if value, ok := a.evalConst(ce.Value); ok && types.IsInteger(target) {
+		if f, isFloat := value.(float64); isFloat && f != math.Trunc(f) {
+			a.errf(ce, "cannot convert %s (%s constant %v) to type %s (truncated)", ce.Value.String(), valueType, f, target)
+			return target
+		}
+	}
+
+	return a.checkOverflow(ce, target)
+}
+
+// funcType builds the type of a function from its parameters and return
+// type annotation. Parameters without a type are of invalid type.
+func (a *Analyzer) funcType(params []*ast.FunctionParameter, result ast.TypeExpression) *types.Func {
+	paramTypes := make([]types.Type, len(params))
+	for i, param := range params {
+		paramTypes[i] = invalidType
+		if param.Type != nil {
+			paramTypes[i] = a.typeOf(param.Type)
+		}
+	}
+
+	var resultType types.Type
+	if result != nil {
+		resultType = a.typeOf(result)
+	}
+
+	return types.NewFunc(paramTypes, resultType)
+}
+
+// typeOf resolves a type annotation to the type it denotes.
+func (a *Analyzer) typeOf(expr ast.TypeExpression) types.Type {
+	switch e := expr.(type) {
+	case *ast.TypeLiteral:
+		if t := types.Lookup(e.Value); t != nil {
+			return t
+		}
+		if e.Token.Type == token.IDENT {
+			return a.lookupType(&ast.Identifier{Token: e.Token, Loc: e.Loc, Value: e.Value})
+		}
+		a.errf(e, "undefined type '%s'", e.Value)
+	case *ast.StructType:
+		return a.structType(e)
+	case *ast.ArrayType:
+		return a.arrayType(e)
+	case *ast.MapType:
+		return a.mapType(e)
+	case *ast.FunctionType:
+		a.indirect++
+		defer func() { a.indirect-- }()
+
+		params := make([]types.Type, len(e.Params))
+		for i, param := range e.Params {
+			params[i] = a.typeOf(param)
+		}
+
+		var result types.Type
+		if e.Result != nil {
+			result = a.typeOf(e.Result)
+		}
+		return types.NewFunc(params, result)
 	}
+	return invalidType
 }
@@ -0,0 +1,78 @@
+package token
+
+import "fmt"
+
+// Pos is a single location in the source text.
+type Pos struct {
+	File   string // name of the source file, may be empty
+	Offset int    // byte offset, starting at 0
+	Line   int    // line number, starting at 1
+	Column int    // column number in runes, starting at 1
+}
+
+// IsValid reports whether the position has been set.
+func (p Pos) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Pos) String() string {
+	if !p.IsValid() {
+		if p.File != "" {
+			return p.File
+		}
+		return "-"
+	}
+
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Span is a half-open source range [Start, End).
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+func NewSpan(start, end Pos) Span {
+	return Span{Start: start, End: end}
+}
+
+// IsValid reports whether the span has a valid start position.
+func (s Span) IsValid() bool {
+	return s.Start.IsValid()
+}
+
+// Join returns the smallest span covering both s and other.
+func (s Span) Join(other Span) Span {
+	if !s.IsValid() {
+		return other
+	}
+	if !other.IsValid() {
+		return s
+	}
+
+	out := s
+	if other.Start.Offset < out.Start.Offset {
+		out.Start = other.Start
+	}
+	if other.End.Offset > out.End.Offset {
+		out.End = other.End
+	}
+
+	return out
+}
+
+func (s Span) String() string {
+	if !s.IsValid() {
+		return s.Start.String()
+	}
+
+	if s.End.Line == s.Start.Line {
+		return fmt.Sprintf("%s-%d", s.Start, s.End.Column)
+	}
+
+	return fmt.Sprintf("%s-%d:%d", s.Start, s.End.Line, s.End.Column)
+}
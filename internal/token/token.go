@@ -5,7 +5,7 @@ import "strings"
 type Token struct {
 	Type TokenType
 	Text string
-	//  TODO: add meta data for token?
+	Span Span // source range the token was read from
 	// Meta any
 }
 
@@ -16,6 +16,20 @@ func New(_type TokenType, text string) Token {
 	}
 }
 
+// NewAt creates a token located at the given source span.
+func NewAt(_type TokenType, text string, span Span) Token {
+	return Token{
+		Type: _type,
+		Text: text,
+		Span: span,
+	}
+}
+
+// Pos returns the start position of the token.
+func (t Token) Pos() Pos {
+	return t.Span.Start
+}
+
 func (t Token) String() string {
 	var buff strings.Builder
 
@@ -20,12 +20,26 @@ const (
 	UINT64
 
 	NUMBER_LITERAL // var a = 1234;
+	HEX_LITERAL    // var a = 0xFF;
+	OCTAL_LITERAL  // var a = 0o17;
+	BINARY_LITERAL // var a = 0b1010;
+
+	// Floating-point
+	FLOAT
+
+	FLOAT_LITERAL // var a = 1.5e3;
 
 	// String
 	STRING
 
 	STRING_LITERAL // var a = "STRING_LITERAL";
 
+	// Boolean
+	BOOL
+
+	TRUE
+	FALSE
+
 	PLUS
 	MINUS
 	DIV
@@ -33,23 +47,48 @@ const (
 
 	ASSIGN
 
+	EQ     // ==
+	NOT_EQ // !=
+	LT     // <
+	GT     // >
+	LT_EQ  // <=
+	GT_EQ  // >=
+
+	BANG // !
+	AND  // &&
+	OR   // ||
+
 	RBRACE
 	LBRACE
 
 	LPAREN
 	RPAREN
 
+	LBRACKET
+	RBRACKET
+
 	IDENT
 
 	SEMICOLON
+	COLON
 	COMMA
+	DOT
 
 	FN
 	FOR
+	IF
+	ELSE
 	VAR
 	CONST
 	PRINT
 	RETURN
+	BREAK
+	CONTINUE
+	TYPE
+	STRUCT
+	MAP
+
+	COMMENT // "// line" or "/* block */"
 
 	ILLEGAL
 	EOF
@@ -69,11 +108,23 @@ var tokenTypes = [...]string{
 	UINT64: "UINT64",
 
 	NUMBER_LITERAL: "NUMBER_LITERAL",
+	HEX_LITERAL:    "HEX_LITERAL",
+	OCTAL_LITERAL:  "OCTAL_LITERAL",
+	BINARY_LITERAL: "BINARY_LITERAL",
+
+	FLOAT: "FLOAT",
+
+	FLOAT_LITERAL: "FLOAT_LITERAL",
 
 	STRING: "STRING",
 
 	STRING_LITERAL: "STRING_LITERAL",
 
+	BOOL: "BOOL",
+
+	TRUE:  "TRUE",
+	FALSE: "FALSE",
+
 	PLUS:  "PLUS",
 	MINUS: "MINUS",
 	DIV:   "DIV",
@@ -81,35 +132,71 @@ var tokenTypes = [...]string{
 
 	ASSIGN: "ASSIGN",
 
+	EQ:     "EQ",
+	NOT_EQ: "NOT_EQ",
+	LT:     "LT",
+	GT:     "GT",
+	LT_EQ:  "LT_EQ",
+	GT_EQ:  "GT_EQ",
+
+	BANG: "BANG",
+	AND:  "AND",
+	OR:   "OR",
+
 	RBRACE: "RBRACE",
 	LBRACE: "LBRACE",
 
 	LPAREN: "LPAREN",
 	RPAREN: "RPAREN",
 
+	LBRACKET: "LBRACKET",
+	RBRACKET: "RBRACKET",
+
 	IDENT: "IDENT",
 
 	SEMICOLON: "SEMICOLON",
+	COLON:     "COLON",
 	COMMA:     "COMMA",
+	DOT:       "DOT",
 
 	FN:     "FN",
 	FOR:    "FOR",
+	IF:     "IF",
+	ELSE:   "ELSE",
 	VAR:    "VAR",
 	CONST:  "CONST",
 	PRINT:  "PRINT",
 	RETURN: "RETURN",
 
+	BREAK:    "BREAK",
+	CONTINUE: "CONTINUE",
+
+	TYPE:   "TYPE",
+	STRUCT: "STRUCT",
+	MAP:    "MAP",
+
+	COMMENT: "COMMENT",
+
 	ILLEGAL: "ILLEGAL",
 	EOF:     "EOF",
 }
 
 var keywords = map[string]TokenType{
-	"const":  CONST,
-	"var":    VAR,
-	"print":  PRINT,
-	"fn":     FN,
-	"for":    FOR,
-	"return": RETURN,
+	"const":    CONST,
+	"var":      VAR,
+	"print":    PRINT,
+	"fn":       FN,
+	"for":      FOR,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"type":     TYPE,
+	"struct":   STRUCT,
+	"map":      MAP,
+	"true":     TRUE,
+	"false":    FALSE,
 }
 
 var operators = map[rune]TokenType{
@@ -120,15 +207,38 @@ var operators = map[rune]TokenType{
 
 	'=': ASSIGN,
 
+	'<': LT,
+	'>': GT,
+
+	'!': BANG,
+
 	// TODO: is operators???
 	';': SEMICOLON,
+	':': COLON,
 	'(': LPAREN,
 	')': RPAREN,
+	'[': LBRACKET,
+	']': RBRACKET,
 	'{': LBRACE,
 	'}': RBRACE,
 	',': COMMA,
+	'.': DOT,
 }
 
+// multiCharOperators holds operators spelled with more than one rune.
+// The lexer prefers the longest match, so "<=" wins over "<".
+var multiCharOperators = map[string]TokenType{
+	"==": EQ,
+	"!=": NOT_EQ,
+	"<=": LT_EQ,
+	">=": GT_EQ,
+	"&&": AND,
+	"||": OR,
+}
+
+// MaxOperatorLen is the length in runes of the longest operator.
+const MaxOperatorLen = 2
+
 var types = map[string]TokenType{
 	"int":   INT,
 	"int8":  INT8,
@@ -142,7 +252,11 @@ var types = map[string]TokenType{
 	"uint32": UINT32,
 	"uint64": UINT64,
 
+	"float": FLOAT,
+
 	"string": STRING,
+
+	"bool": BOOL,
 }
 
 func (tt TokenType) String() string {
@@ -182,6 +296,34 @@ func IsOperator(char rune) (TokenType, bool) {
 	return tt, ok
 }
 
+// LookupOperator returns the operator spelled exactly as s.
+func LookupOperator(s string) (TokenType, bool) {
+	if tt, ok := multiCharOperators[s]; ok {
+		return tt, ok
+	}
+
+	if r := []rune(s); len(r) == 1 {
+		return IsOperator(r[0])
+	}
+
+	return ILLEGAL, false
+}
+
+// IsOperatorStart reports whether some operator begins with char.
+func IsOperatorStart(char rune) bool {
+	if _, ok := operators[char]; ok {
+		return true
+	}
+
+	for op := range multiCharOperators {
+		if []rune(op)[0] == char {
+			return true
+		}
+	}
+
+	return false
+}
+
 func IsKeyword(s string) (TokenType, bool) {
 	tt, ok := keywords[s]
 	return tt, ok
@@ -205,7 +347,7 @@ func (t Token) IsType() bool {
 	switch t.Type {
 	case INT, INT8, INT16, INT32, INT64,
 		UINT, UINT8, UINT16, UINT32, UINT64,
-		STRING:
+		FLOAT, STRING, BOOL:
 		return true
 	default:
 		return false
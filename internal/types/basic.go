@@ -0,0 +1,101 @@
+package types
+
+// BasicKind identifies a basic type.
+type BasicKind int
+
+const (
+	Invalid BasicKind = iota // the type of an expression with an error
+	Void                     // the result of calling a function without a return type
+
+	Bool
+	String
+
+	Int
+	Int8
+	Int16
+	Int32
+	Int64
+
+	Uint
+	Uint8
+	Uint16
+	Uint32
+	Uint64
+
+	Float
+
+	// Numeric literals and constant expressions built from them are
+	// untyped until they meet a typed operand or are assigned.
+	UntypedInt
+	UntypedFloat
+)
+
+// basicInfo is a set of properties of a basic type.
+type basicInfo int
+
+const (
+	isBoolean basicInfo = 1 << iota
+	isInteger
+	isUnsigned
+	isFloat
+	isString
+	isUntyped
+
+	isNumeric = isInteger | isFloat
+	isOrdered = isNumeric | isString
+)
+
+// Basic is a predeclared type such as int or string.
+type Basic struct {
+	kind BasicKind
+	info basicInfo
+	name string
+}
+
+func (b *Basic) Kind() BasicKind  { return b.kind }
+func (b *Basic) Name() string     { return b.name }
+func (b *Basic) Underlying() Type { return b }
+func (b *Basic) String() string   { return b.name }
+
+// Typ holds the basic types, indexed by kind.
+var Typ = [...]*Basic{
+	Invalid: {Invalid, 0, "unknown type"},
+	Void:    {Void, 0, "void"},
+
+	Bool:   {Bool, isBoolean, "bool"},
+	String: {String, isString, "string"},
+
+	Int:   {Int, isInteger, "int"},
+	Int8:  {Int8, isInteger, "int8"},
+	Int16: {Int16, isInteger, "int16"},
+	Int32: {Int32, isInteger, "int32"},
+	Int64: {Int64, isInteger, "int64"},
+
+	Uint:   {Uint, isInteger | isUnsigned, "uint"},
+	Uint8:  {Uint8, isInteger | isUnsigned, "uint8"},
+	Uint16: {Uint16, isInteger | isUnsigned, "uint16"},
+	Uint32: {Uint32, isInteger | isUnsigned, "uint32"},
+	Uint64: {Uint64, isInteger | isUnsigned, "uint64"},
+
+	Float: {Float, isFloat, "float"},
+
+	UntypedInt:   {UntypedInt, isInteger | isUntyped, "untyped int"},
+	UntypedFloat: {UntypedFloat, isFloat | isUntyped, "untyped float"},
+}
+
+// universe maps the name of each predeclared type to the type.
+var universe = map[string]Type{}
+
+func init() {
+	for _, b := range Typ {
+		if b.kind != Invalid && b.kind != Void && b.info&isUntyped == 0 {
+			universe[b.name] = b
+		}
+	}
+}
+
+// Lookup returns the predeclared type with the given name, or nil if there
+// is none.
+func Lookup(name string) Type {
+	return universe[name]
+}
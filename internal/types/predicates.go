@@ -0,0 +1,196 @@
+package types
+
+import "math/big"
+
+func infoOf(t Type) basicInfo {
+	if t == nil {
+		return 0
+	}
+	if b, ok := t.Underlying().(*Basic); ok {
+		return b.info
+	}
+	return 0
+}
+
+func IsBoolean(t Type) bool  { return infoOf(t)&isBoolean != 0 }
+func IsInteger(t Type) bool  { return infoOf(t)&isInteger != 0 }
+func IsUnsigned(t Type) bool { return infoOf(t)&isUnsigned != 0 }
+func IsFloat(t Type) bool    { return infoOf(t)&isFloat != 0 }
+func IsNumeric(t Type) bool  { return infoOf(t)&isNumeric != 0 }
+func IsString(t Type) bool   { return infoOf(t)&isString != 0 }
+func IsOrdered(t Type) bool  { return infoOf(t)&isOrdered != 0 }
+func IsUntyped(t Type) bool  { return infoOf(t)&isUntyped != 0 }
+
+// IsInvalid reports whether t is the type of an expression with an error.
+func IsInvalid(t Type) bool {
+	return t == Typ[Invalid]
+}
+
+// Identical reports whether x and y are the same type.
+func Identical(x, y Type) bool {
+	if x == y {
+		return true
+	}
+
+	switch x := x.(type) {
+	case *Basic:
+		y, ok := y.(*Basic)
+		return ok && x.kind == y.kind
+	case *Func:
+		y, ok := y.(*Func)
+		if !ok || len(x.Params) != len(y.Params) {
+			return false
+		}
+		for i := range x.Params {
+			if !Identical(x.Params[i], y.Params[i]) {
+				return false
+			}
+		}
+		if x.Result == nil || y.Result == nil {
+			return x.Result == nil && y.Result == nil
+		}
+		return Identical(x.Result, y.Result)
+	case *Array:
+		y, ok := y.(*Array)
+		return ok && x.Len == y.Len && Identical(x.Elem, y.Elem)
+	case *Slice:
+		y, ok := y.(*Slice)
+		return ok && Identical(x.Elem, y.Elem)
+	case *Map:
+		y, ok := y.(*Map)
+		return ok && Identical(x.Key, y.Key) && Identical(x.Elem, y.Elem)
+	case *Struct:
+		y, ok := y.(*Struct)
+		if !ok || len(x.Fields) != len(y.Fields) {
+			return false
+		}
+		for i := range x.Fields {
+			if x.Fields[i].Name != y.Fields[i].Name || !Identical(x.Fields[i].Type, y.Fields[i].Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		// Named types are identical only to themselves.
+		return false
+	}
+}
+
+// Comparable reports whether values of type t can be compared with == and
+// used as map keys. Slices, maps and functions cannot, nor can arrays and
+// structs that contain them.
+func Comparable(t Type) bool {
+	switch u := t.Underlying().(type) {
+	case *Basic:
+		return u.kind != Void
+	case *Array:
+		return Comparable(u.Elem)
+	case *Struct:
+		for _, f := range u.Fields {
+			if !Comparable(f.Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// AssignableTo reports whether a value of type v can be assigned to a
+// variable of type t. The invalid type is assignable in both directions so
+// that one error does not cascade.
+func AssignableTo(v, t Type) bool {
+	if IsInvalid(v) || IsInvalid(t) || Identical(v, t) {
+		return true
+	}
+
+	if IsUntyped(v) {
+		switch v.(*Basic).kind {
+		case UntypedInt:
+			return IsNumeric(t)
+		case UntypedFloat:
+			return IsFloat(t)
+		}
+	}
+
+	// A value may move between a named type and an unnamed type with the
+	// same underlying type.
+	_, vNamed := v.(*Named)
+	_, tNamed := t.(*Named)
+	return (!vNamed || !tNamed) && Identical(v.Underlying(), t.Underlying())
+}
+
+// ConvertibleTo reports whether a value of type v can be explicitly
+// converted to type t. Any numeric type converts to any other.
+func ConvertibleTo(v, t Type) bool {
+	if AssignableTo(v, t) || Identical(v.Underlying(), t.Underlying()) {
+		return true
+	}
+	return IsNumeric(v) && IsNumeric(t)
+}
+
+// Default returns the type an untyped value takes when nothing else
+// determines it, as in var a = 1; A slice or map of untyped values, the
+// type of a literal such as [1, 2], becomes a slice or map of their
+// default types. Typed types are returned unchanged.
+func Default(t Type) Type {
+	switch t := t.(type) {
+	case *Basic:
+		switch t.kind {
+		case UntypedInt:
+			return Typ[Int]
+		case UntypedFloat:
+			return Typ[Float]
+		}
+	case *Slice:
+		if elem := Default(t.Elem); elem != t.Elem {
+			return NewSlice(elem)
+		}
+	case *Map:
+		if key, elem := Default(t.Key), Default(t.Elem); key != t.Key || elem != t.Elem {
+			return NewMap(key, elem)
+		}
+	}
+	return t
+}
+
+// Representable reports whether the integer constant v fits in type t.
+// Every value fits a type that is not a sized integer type.
+func Representable(v *big.Int, t Type) bool {
+	b, ok := t.Underlying().(*Basic)
+	if !ok {
+		return true
+	}
+
+	bounds, ok := intBounds[b.kind]
+	if !ok {
+		return true
+	}
+	return v.Cmp(bounds[0]) >= 0 && v.Cmp(bounds[1]) <= 0
+}
+
+// intBounds holds the smallest and largest value of each integer type.
+// int and uint are 64 bits wide.
+var intBounds = map[BasicKind][2]*big.Int{
+	Int:    signedBounds(64),
+	Int8:   signedBounds(8),
+	Int16:  signedBounds(16),
+	Int32:  signedBounds(32),
+	Int64:  signedBounds(64),
+	Uint:   unsignedBounds(64),
+	Uint8:  unsignedBounds(8),
+	Uint16: unsignedBounds(16),
+	Uint32: unsignedBounds(32),
+	Uint64: unsignedBounds(64),
+}
+
+func signedBounds(bits uint) [2]*big.Int {
+	limit := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	return [2]*big.Int{new(big.Int).Neg(limit), new(big.Int).Sub(limit, big.NewInt(1))}
+}
+
+func unsignedBounds(bits uint) [2]*big.Int {
+	limit := new(big.Int).Lsh(big.NewInt(1), bits)
+	return [2]*big.Int{big.NewInt(0), new(big.Int).Sub(limit, big.NewInt(1))}
+}
@@ -0,0 +1,148 @@
+// Package types declares the types of the language and the rules for
+// comparing, assigning and converting values of those types.
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Type is the type of a value.
+type Type interface {
+	// Underlying returns the underlying type of a type. The underlying
+	// type of a type that is not named is the type itself.
+	Underlying() Type
+
+	// String returns the type as it is written in source.
+	String() string
+}
+
+// Func is the type of a function, e.g. fn(int, string) bool.
+type Func struct {
+	Params []Type
+	Result Type // nil if the function has no return type
+}
+
+// NewFunc returns the type of a function with the given parameter and
+// result types. result is nil for a function without a return type.
+func NewFunc(params []Type, result Type) *Func {
+	return &Func{Params: params, Result: result}
+}
+
+func (f *Func) Underlying() Type { return f }
+
+func (f *Func) String() string {
+	params := make([]string, len(f.Params))
+	for i, p := range f.Params {
+		params[i] = p.String()
+	}
+
+	out := "fn(" + strings.Join(params, ", ") + ")"
+	if f.Result != nil {
+		out += " " + f.Result.String()
+	}
+	return out
+}
+
+// Array is a fixed-size array type, e.g. [3]int.
+type Array struct {
+	Len  int64
+	Elem Type
+}
+
+// NewArray returns the type of an array of length elements of type elem.
+func NewArray(elem Type, length int64) *Array {
+	return &Array{Len: length, Elem: elem}
+}
+
+func (a *Array) Underlying() Type { return a }
+func (a *Array) String() string   { return "[" + strconv.FormatInt(a.Len, 10) + "]" + a.Elem.String() }
+
+// Slice is a slice type, e.g. []int.
+type Slice struct {
+	Elem Type
+}
+
+// NewSlice returns the type of a slice of elements of type elem.
+func NewSlice(elem Type) *Slice {
+	return &Slice{Elem: elem}
+}
+
+func (s *Slice) Underlying() Type { return s }
+func (s *Slice) String() string   { return "[]" + s.Elem.String() }
+
+// Map is a map type, e.g. map[string]int.
+type Map struct {
+	Key  Type
+	Elem Type
+}
+
+// NewMap returns the type of a map from key to elem.
+func NewMap(key, elem Type) *Map {
+	return &Map{Key: key, Elem: elem}
+}
+
+func (m *Map) Underlying() Type { return m }
+func (m *Map) String() string   { return "map[" + m.Key.String() + "]" + m.Elem.String() }
+
+// Struct is a struct type, e.g. struct{x int; y int}.
+type Struct struct {
+	Fields []*Field
+}
+
+// Field is a field of a struct type.
+type Field struct {
+	Name string
+	Type Type
+}
+
+// NewStruct returns a struct type with the given fields.
+func NewStruct(fields []*Field) *Struct {
+	return &Struct{Fields: fields}
+}
+
+// Field returns the field with the given name, or nil if there is none.
+func (s *Struct) Field(name string) *Field {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *Struct) Underlying() Type { return s }
+
+func (s *Struct) String() string {
+	fields := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		fields[i] = f.Name + " " + f.Type.String()
+	}
+	return "struct{" + strings.Join(fields, "; ") + "}"
+}
+
+// Named is a type declared with a name. Two named types are identical only
+// if they are the same declaration.
+type Named struct {
+	name       string
+	underlying Type
+}
+
+// NewNamed returns a new named type. The underlying type of a named type
+// is never itself named.
+func NewNamed(name string, underlying Type) *Named {
+	if underlying != nil {
+		underlying = underlying.Underlying()
+	}
+	return &Named{name: name, underlying: underlying}
+}
+
+// Name returns the name of the type.
+func (n *Named) Name() string { return n.name }
+
+// SetUnderlying sets the underlying type of a named type whose
+// declaration refers to itself and so could not be completed by NewNamed.
+func (n *Named) SetUnderlying(underlying Type) { n.underlying = underlying.Underlying() }
+
+func (n *Named) Underlying() Type { return n.underlying }
+func (n *Named) String() string   { return n.name }
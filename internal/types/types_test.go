@@ -0,0 +1,172 @@
+package types_test
+
+import (
+	"math/big"
+	"testing"
+
+	"ixion/internal/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	intType     = types.Typ[types.Int]
+	int8Type    = types.Typ[types.Int8]
+	uint8Type   = types.Typ[types.Uint8]
+	floatType   = types.Typ[types.Float]
+	stringType  = types.Typ[types.String]
+	boolType    = types.Typ[types.Bool]
+	untypedInt  = types.Typ[types.UntypedInt]
+	untypedReal = types.Typ[types.UntypedFloat]
+	invalid     = types.Typ[types.Invalid]
+)
+
+func TestLookup(t *testing.T) {
+	assert.Equal(t, types.Type(int8Type), types.Lookup("int8"))
+	assert.Equal(t, types.Type(floatType), types.Lookup("float"))
+	assert.Nil(t, types.Lookup("untyped int"))
+	assert.Nil(t, types.Lookup("void"))
+	assert.Nil(t, types.Lookup("nope"))
+}
+
+func TestFuncString(t *testing.T) {
+	assert.Equal(t, "fn()", types.NewFunc(nil, nil).String())
+	assert.Equal(t, "fn(int, string) bool",
+		types.NewFunc([]types.Type{intType, stringType}, boolType).String())
+	assert.Equal(t, "fn(fn(int)) fn() int",
+		types.NewFunc(
+			[]types.Type{types.NewFunc([]types.Type{intType}, nil)},
+			types.NewFunc(nil, intType),
+		).String())
+}
+
+func TestStruct(t *testing.T) {
+	point := types.NewStruct([]*types.Field{{Name: "x", Type: intType}, {Name: "y", Type: intType}})
+	assert.Equal(t, "struct{x int; y int}", point.String())
+	assert.Equal(t, types.Type(intType), point.Field("y").Type)
+	assert.Nil(t, point.Field("z"))
+}
+
+func TestIdentical(t *testing.T) {
+	celsius := types.NewNamed("Celsius", floatType)
+	fahrenheit := types.NewNamed("Fahrenheit", floatType)
+
+	testCases := []struct {
+		name string
+		x, y types.Type
+		want bool
+	}{
+		{"same basic", intType, intType, true},
+		{"different basic", intType, int8Type, false},
+		{"same signature", types.NewFunc([]types.Type{intType}, boolType), types.NewFunc([]types.Type{intType}, boolType), true},
+		{"different parameter", types.NewFunc([]types.Type{intType}, nil), types.NewFunc([]types.Type{int8Type}, nil), false},
+		{"different arity", types.NewFunc([]types.Type{intType}, nil), types.NewFunc(nil, nil), false},
+		{"result and no result", types.NewFunc(nil, intType), types.NewFunc(nil, nil), false},
+		{"same fields", types.NewStruct([]*types.Field{{Name: "x", Type: intType}}), types.NewStruct([]*types.Field{{Name: "x", Type: intType}}), true},
+		{"different field name", types.NewStruct([]*types.Field{{Name: "x", Type: intType}}), types.NewStruct([]*types.Field{{Name: "y", Type: intType}}), false},
+		{"different field type", types.NewStruct([]*types.Field{{Name: "x", Type: intType}}), types.NewStruct([]*types.Field{{Name: "x", Type: int8Type}}), false},
+		{"same array", types.NewArray(intType, 3), types.NewArray(intType, 3), true},
+		{"different array length", types.NewArray(intType, 3), types.NewArray(intType, 4), false},
+		{"array and slice", types.NewArray(intType, 3), types.NewSlice(intType), false},
+		{"same slice", types.NewSlice(stringType), types.NewSlice(stringType), true},
+		{"different slice element", types.NewSlice(stringType), types.NewSlice(boolType), false},
+		{"same map", types.NewMap(stringType, intType), types.NewMap(stringType, intType), true},
+		{"different map key", types.NewMap(stringType, intType), types.NewMap(intType, intType), false},
+		{"same named", celsius, celsius, true},
+		{"different named", celsius, fahrenheit, false},
+		{"named and underlying", celsius, floatType, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, types.Identical(tt.x, tt.y))
+		})
+	}
+}
+
+func TestComparable(t *testing.T) {
+	assert.True(t, types.Comparable(stringType))
+	assert.True(t, types.Comparable(types.NewArray(intType, 2)))
+	assert.True(t, types.Comparable(types.NewStruct([]*types.Field{{Name: "x", Type: floatType}})))
+	assert.True(t, types.Comparable(types.NewNamed("Celsius", floatType)))
+	assert.False(t, types.Comparable(types.NewSlice(intType)))
+	assert.False(t, types.Comparable(types.NewMap(stringType, intType)))
+	assert.False(t, types.Comparable(types.NewFunc(nil, nil)))
+	assert.False(t, types.Comparable(types.NewArray(types.NewSlice(intType), 2)))
+	assert.False(t, types.Comparable(types.NewStruct([]*types.Field{{Name: "f", Type: types.NewFunc(nil, nil)}})))
+}
+
+func TestAssignableTo(t *testing.T) {
+	celsius := types.NewNamed("Celsius", floatType)
+	fahrenheit := types.NewNamed("Fahrenheit", floatType)
+
+	testCases := []struct {
+		name   string
+		value  types.Type
+		target types.Type
+		want   bool
+	}{
+		{"identical", intType, intType, true},
+		{"distinct integer types", int8Type, intType, false},
+		{"untyped int to integer", untypedInt, uint8Type, true},
+		{"untyped int to float", untypedInt, floatType, true},
+		{"untyped float to float", untypedReal, floatType, true},
+		{"untyped float to integer", untypedReal, intType, false},
+		{"untyped int to string", untypedInt, stringType, false},
+		{"invalid value", invalid, stringType, true},
+		{"invalid target", boolType, invalid, true},
+		{"underlying to named", floatType, celsius, true},
+		{"named to underlying", celsius, floatType, true},
+		{"untyped to named", untypedInt, celsius, true},
+		{"between named types", celsius, fahrenheit, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, types.AssignableTo(tt.value, tt.target))
+		})
+	}
+}
+
+func TestConvertibleTo(t *testing.T) {
+	celsius := types.NewNamed("Celsius", floatType)
+	fahrenheit := types.NewNamed("Fahrenheit", floatType)
+
+	assert.True(t, types.ConvertibleTo(int8Type, intType))
+	assert.True(t, types.ConvertibleTo(floatType, uint8Type))
+	assert.True(t, types.ConvertibleTo(celsius, fahrenheit))
+	assert.False(t, types.ConvertibleTo(stringType, intType))
+	assert.False(t, types.ConvertibleTo(boolType, intType))
+}
+
+func TestDefault(t *testing.T) {
+	assert.Equal(t, types.Type(intType), types.Default(untypedInt))
+	assert.Equal(t, types.Type(floatType), types.Default(untypedReal))
+	assert.Equal(t, types.Type(int8Type), types.Default(int8Type))
+	assert.Equal(t, "[]int", types.Default(types.NewSlice(untypedInt)).String())
+	assert.Equal(t, "[][]float", types.Default(types.NewSlice(types.NewSlice(untypedReal))).String())
+	assert.Equal(t, "[2]uint8", types.Default(types.NewArray(uint8Type, 2)).String())
+	assert.Equal(t, "map[string]float", types.Default(types.NewMap(stringType, untypedReal)).String())
+}
+
+func TestRepresentable(t *testing.T) {
+	testCases := []struct {
+		value int64
+		typ   types.Type
+		want  bool
+	}{
+		{127, int8Type, true},
+		{128, int8Type, false},
+		{-128, int8Type, true},
+		{-129, int8Type, false},
+		{255, uint8Type, true},
+		{-1, uint8Type, false},
+		{1 << 62, intType, true},
+		{-1, floatType, true},
+		{300, types.NewNamed("Small", int8Type), false},
+	}
+
+	for _, tt := range testCases {
+		assert.Equal(t, tt.want, types.Representable(big.NewInt(tt.value), tt.typ), "%d as %s", tt.value, tt.typ)
+	}
+}